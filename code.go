@@ -0,0 +1,184 @@
+package eris
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Code is a category of error that can be attached to any error via eris.WithCode. The set of codes mirrors the
+// canonical gRPC status codes, so errors can be translated into API responses without matching on error messages.
+type Code int
+
+const (
+	// OK indicates that no code has been attached to an error.
+	OK Code = iota
+	// Canceled indicates the operation was canceled, typically by the caller.
+	Canceled
+	// Unknown indicates an unknown error, e.g. an error without a code.
+	Unknown
+	// InvalidArgument indicates the client specified an invalid argument.
+	InvalidArgument
+	// DeadlineExceeded indicates the operation expired before completion.
+	DeadlineExceeded
+	// NotFound indicates some requested entity was not found.
+	NotFound
+	// AlreadyExists indicates an attempt to create an entity failed because one already exists.
+	AlreadyExists
+	// PermissionDenied indicates the caller does not have permission to execute the specified operation.
+	PermissionDenied
+	// ResourceExhausted indicates some resource has been exhausted, e.g. a per-user quota.
+	ResourceExhausted
+	// FailedPrecondition indicates the operation was rejected because the system is not in a state required for
+	// the operation's execution.
+	FailedPrecondition
+	// Aborted indicates the operation was aborted, typically due to a concurrency issue.
+	Aborted
+	// OutOfRange indicates the operation was attempted past the valid range.
+	OutOfRange
+	// Unimplemented indicates the operation is not implemented or not supported.
+	Unimplemented
+	// Internal indicates an internal error, i.e. some invariant expected by the system has been broken.
+	Internal
+	// Unavailable indicates the service is currently unavailable and the operation may be retried.
+	Unavailable
+	// DataLoss indicates unrecoverable data loss or corruption.
+	DataLoss
+	// Unauthenticated indicates the request does not have valid authentication credentials.
+	Unauthenticated
+)
+
+var codeNames = [...]string{
+	OK:                 "OK",
+	Canceled:           "Canceled",
+	Unknown:            "Unknown",
+	InvalidArgument:    "InvalidArgument",
+	DeadlineExceeded:   "DeadlineExceeded",
+	NotFound:           "NotFound",
+	AlreadyExists:      "AlreadyExists",
+	PermissionDenied:   "PermissionDenied",
+	ResourceExhausted:  "ResourceExhausted",
+	FailedPrecondition: "FailedPrecondition",
+	Aborted:            "Aborted",
+	OutOfRange:         "OutOfRange",
+	Unimplemented:      "Unimplemented",
+	Internal:           "Internal",
+	Unavailable:        "Unavailable",
+	DataLoss:           "DataLoss",
+	Unauthenticated:    "Unauthenticated",
+}
+
+var codeHTTPStatuses = [...]int{
+	OK:                 http.StatusOK,
+	Canceled:           499, // client closed request
+	Unknown:            http.StatusInternalServerError,
+	InvalidArgument:    http.StatusBadRequest,
+	DeadlineExceeded:   http.StatusGatewayTimeout,
+	NotFound:           http.StatusNotFound,
+	AlreadyExists:      http.StatusConflict,
+	PermissionDenied:   http.StatusForbidden,
+	ResourceExhausted:  http.StatusTooManyRequests,
+	FailedPrecondition: http.StatusBadRequest,
+	Aborted:            http.StatusConflict,
+	OutOfRange:         http.StatusBadRequest,
+	Unimplemented:      http.StatusNotImplemented,
+	Internal:           http.StatusInternalServerError,
+	Unavailable:        http.StatusServiceUnavailable,
+	DataLoss:           http.StatusInternalServerError,
+	Unauthenticated:    http.StatusUnauthorized,
+}
+
+// String returns the name of the code (e.g. "NotFound").
+func (c Code) String() string {
+	if c >= 0 && int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return fmt.Sprintf("Code(%d)", int(c))
+}
+
+// MarshalText encodes the code as its name, so codes appear as e.g. "NotFound" in JSON.
+func (c Code) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes a code from its name as returned by String.
+func (c *Code) UnmarshalText(text []byte) error {
+	for code, name := range codeNames {
+		if name == string(text) {
+			*c = Code(code)
+			return nil
+		}
+	}
+	var code int
+	if _, err := fmt.Sscanf(string(text), "Code(%d)", &code); err == nil {
+		*c = Code(code)
+		return nil
+	}
+	return fmt.Errorf("eris: unknown code %q", text)
+}
+
+// HTTPStatus returns the HTTP status code corresponding to c, following the same mapping as the gRPC HTTP gateway.
+// Unrecognized codes map to 500 (Internal Server Error).
+func (c Code) HTTPStatus() int {
+	if c >= 0 && int(c) < len(codeHTTPStatuses) {
+		return codeHTTPStatuses[c]
+	}
+	return http.StatusInternalServerError
+}
+
+// WithCode attaches a code to err. Codes attached to outer errors take precedence over codes further down the
+// chain, and attaching OK has no effect. The code is attached to a copy of err prepared the same way as by
+// WithFields, so err itself is never modified and sentinel errors can be declared with a code:
+//
+//	NotFound := eris.WithCode(eris.New("not found"), eris.NotFound)
+//	...
+//	http.Error(w, err.Error(), eris.CodeOf(err).HTTPStatus())
+func WithCode(err error, code Code) error {
+	return annotate(err, func(m *meta) {
+		if code != OK {
+			m.code = code
+		}
+	})
+}
+
+// CodeOf returns the outermost code attached to an error in err's chain. The branches of multi-errors are searched
+// in order if no code is found before them. OK is returned if err is nil and Unknown is returned if no code is found.
+func CodeOf(err error) Code {
+	if err == nil {
+		return OK
+	}
+	if code := codeOf(err); code != OK {
+		return code
+	}
+	return Unknown
+}
+
+func codeOf(err error) Code {
+	for ; err != nil; err = Unwrap(err) {
+		switch e := err.(type) {
+		case *rootError:
+			if e.code != OK {
+				return e.code
+			}
+		case *wrapError:
+			if e.code != OK {
+				return e.code
+			}
+		case *joinError:
+			if e.code != OK {
+				return e.code
+			}
+			for _, branch := range e.errs {
+				if code := codeOf(branch); code != OK {
+					return code
+				}
+			}
+		case interface{ Unwrap() []error }:
+			for _, branch := range e.Unwrap() {
+				if code := codeOf(branch); code != OK {
+					return code
+				}
+			}
+		}
+	}
+	return OK
+}
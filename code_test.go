@@ -0,0 +1,83 @@
+package eris_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func TestCodeOf(t *testing.T) {
+	globalErr := eris.WithCode(eris.New("not found"), eris.NotFound)
+
+	tests := map[string]struct {
+		err    error     // error with codes
+		output eris.Code // expected code
+	}{
+		"nil error": {
+			err:    nil,
+			output: eris.OK,
+		},
+		"error without code": {
+			err:    eris.Wrap(errors.New("external error"), "additional context"),
+			output: eris.Unknown,
+		},
+		"code on root error": {
+			err:    eris.Wrap(globalErr, "additional context"),
+			output: eris.NotFound,
+		},
+		"code on external error": {
+			err:    eris.WithCode(errors.New("external error"), eris.Unavailable),
+			output: eris.Unavailable,
+		},
+		"outermost code wins": {
+			err:    eris.WithCode(eris.Wrap(globalErr, "additional context"), eris.PermissionDenied),
+			output: eris.PermissionDenied,
+		},
+		"attaching OK has no effect": {
+			err:    eris.WithCode(globalErr, eris.OK),
+			output: eris.NotFound,
+		},
+		"code in multi-error branch": {
+			err:    eris.Join(eris.New("root error"), globalErr),
+			output: eris.NotFound,
+		},
+	}
+
+	for desc, tc := range tests {
+		if code := eris.CodeOf(tc.err); code != tc.output {
+			t.Errorf("%v: expected code { %v } got { %v }", desc, tc.output, code)
+		}
+	}
+
+	if !eris.Is(eris.Wrap(globalErr, "additional context"), globalErr) {
+		t.Errorf("expected error with code to still match the original error")
+	}
+}
+
+func TestCodeHTTPStatus(t *testing.T) {
+	tests := map[eris.Code]struct {
+		name   string // expected name
+		status int    // expected HTTP status
+	}{
+		eris.OK:                {"OK", http.StatusOK},
+		eris.Canceled:          {"Canceled", 499},
+		eris.InvalidArgument:   {"InvalidArgument", http.StatusBadRequest},
+		eris.NotFound:          {"NotFound", http.StatusNotFound},
+		eris.AlreadyExists:     {"AlreadyExists", http.StatusConflict},
+		eris.ResourceExhausted: {"ResourceExhausted", http.StatusTooManyRequests},
+		eris.Unavailable:       {"Unavailable", http.StatusServiceUnavailable},
+		eris.Unauthenticated:   {"Unauthenticated", http.StatusUnauthorized},
+		eris.Code(100):         {"Code(100)", http.StatusInternalServerError},
+	}
+
+	for code, tc := range tests {
+		if name := code.String(); name != tc.name {
+			t.Errorf("expected name { %v } got { %v }", tc.name, name)
+		}
+		if status := code.HTTPStatus(); status != tc.status {
+			t.Errorf("%v: expected HTTP status { %v } got { %v }", tc.name, tc.status, status)
+		}
+	}
+}
@@ -17,15 +17,29 @@
 //
 // When external error types are wrapped with additional context, a root error
 // is first created from the original error. This creates a stack trace for the
-// error and allows it to function with the rest of the `eris` package.
+// error and allows it to function with the rest of the `eris` package. The
+// original error is kept in the chain and can be retrieved via eris.As.
+// If the original error already carries a stack trace in the style of
+// github.com/pkg/errors (i.e. via a StackTrace method), that stack trace is
+// reused instead, and chains built via Cause methods are followed like Unwrap
+// chains.
+//
+// External errors that wrap eris errors themselves, e.g. via
+// fmt.Errorf("...: %w", err), are kept as they are since the errors beneath
+// them already carry a stack trace. They show up in the middle of the chain
+// when the error is printed or unpacked. This includes external multi-errors
+// such as the ones created via errors.Join, whose branches are unpacked like
+// the branches of eris.Join.
 //
 // Wrapping errors with additional context
 //
 // eris.Wrap adds context to an error while preserving the type of the
 // original error. This method behaves differently for each error type. For
-// root errors, the stack trace is reset to the current callers which ensures
-// traces are correct when using global/sentinel error values. Wrapped error
-// types are simply wrapped with the new context. For external types (i.e.
+// root errors, a copy of the error is created with the stack trace set to the
+// current callers which ensures traces are correct when using global/sentinel
+// error values. The original root error is never modified, so sentinels are
+// safe to wrap from multiple goroutines. Wrapped error types are simply
+// wrapped with the new context. For external types (i.e.
 // something other than root or wrap errors), a new root error is created for
 // the original error and then it's wrapped with the additional context.
 //
@@ -35,15 +49,70 @@
 //      return eris.Wrapf(err, "error getting resource '%v'", id)
 //    }
 //
+// eris.Errorf and eris.Wrapf support the %w verb, so fmt.Errorf call sites
+// can be migrated without losing the errors they wrap. A format ending with
+// ": %w" wraps the operand like eris.Wrapf, and errors wrapped via %w
+// anywhere else in the format (including several %w verbs) are linked into
+// the chain so eris.Is and eris.As find them. If there are several %w verbs,
+// or the operand contains eris errors, the operands show up as the branches
+// of the root error, like the branches of eris.Join, so their stack traces
+// are kept.
+//
+// Combining multiple errors
+//
+// eris.Join and eris.Append combine several errors into a multi-error. Each
+// error becomes a separate branch with its own stack trace, and eris.Is,
+// eris.As, eris.Cause, and eris.Unpack all traverse every branch.
+//
+//    var err error
+//    for _, id := range ids {
+//      if _, getErr := db.Get(id); getErr != nil {
+//        err = eris.Append(err, eris.Wrapf(getErr, "error getting resource '%v'", id))
+//      }
+//    }
+//    return err
+//
+// Recovering from panics
+//
+// eris.Recover turns a panic into an error when it's deferred, and
+// eris.RecoverPanic converts a value returned by recover into an error. The
+// stack trace of the error points to where the panic happened, and if the
+// panic value is an error (e.g. a runtime.Error), it's kept in the chain for
+// eris.Is and eris.As.
+//
+//    func GetResource(id string) (res *Resource, err error) {
+//      defer eris.Recover(&err)
+//      ...
+//    }
+//
+// Collecting errors from goroutines
+//
+// eris.Group runs functions in separate goroutines like errgroup.Group.
+// Errors are wrapped with the frame of the Go call that started the
+// goroutine, and panics are recovered into errors. Group.Wait returns the
+// first error, and Group.WaitAll returns a multi-error containing all of
+// them. eris.GroupWithContext also cancels a context when the first error
+// occurs.
+//
+//    var g eris.Group
+//    for _, id := range ids {
+//      id := id
+//      g.Go(func() error {
+//        return process(id)
+//      })
+//    }
+//    err := g.WaitAll()
+//
 // Inspecting error types
 //
 // The eris package provides a few ways to inspect and compare error types.
 // eris.Is returns true if a particular error appears anywhere in the error
-// chain, and eris.Cause returns the root cause of the error. Currently,
-// eris.Is works simply by comparing error messages with each other. If an
-// error contains a particular error message anywhere in its chain (e.g. "not
-// found"), it's defined to be that error type (i.e. eris.Is will return
-// true).
+// chain, eris.As finds the first error in the chain of a particular type,
+// and eris.Cause returns the root cause of the error. eris.Is matches
+// errors by identity: a root error such as a global "not found" sentinel
+// only matches itself, even after it has been wrapped, and an unrelated error
+// with the same message never matches. eris.IsMessage is available for code
+// that relies on comparing error messages with each other.
 //
 //    NotFound := eris.New("not found")
 //    _, err := db.Get(id)
@@ -53,6 +122,14 @@
 //      return eris.Wrapf(err, "error getting resource '%v'", id)
 //    }
 //
+//    _, err := os.Open(path)
+//    err = eris.Wrapf(err, "error opening file '%v'", path)
+//    // extract the original error type
+//    var pathErr *os.PathError
+//    if eris.As(err, &pathErr) {
+//      log.Printf("failed to %v %v", pathErr.Op, pathErr.Path)
+//    }
+//
 //    NotFound := eris.New("not found")
 //    _, err := db.Get(id)
 //    // compare the cause to some sentinel value
@@ -84,16 +161,91 @@
 //
 //    # output with the stack trace
 //    error getting resource 'example-id'
-//      api.GetResource: /path/to/file/api.go: 30
+//      api.GetResource: api/api.go: 30
 //    not found
-//      api.GetResource: /path/to/file/api.go: 30
-//      db.Get: /path/to/file/db.go: 99
-//      runtime.goexit: /path/to/go/src/libexec/src/runtime/asm_amd64.s: 1337
+//      api.GetResource: api/api.go: 30
+//      db.Get: db/db.go: 99
 //
 // The first layer of the full error output shows a message ("error getting
 // resource 'example-id'") and a single stack frame. The next layer shows the
 // root error ("not found") and the full stack trace.
 //
+// Stack traces are also available without formatting errors as strings.
+// eris.StackTrace returns the frames of the root error in the chain, and
+// eris.StackPCs returns their raw program counters. eris errors also have a
+// StackTrace() []uintptr method, which error reporters such as the Sentry SDK
+// detect the same way as the stack traces of github.com/pkg/errors.
+//
+// File paths are shown relative to the root of their module, and paths of
+// the standard library start with "$GOROOT", so traces don't depend on where
+// the program was built. eris.SetPathRewriters replaces these rules.
+//
+// Frames of the Go runtime, the testing and net/http packages, and vendored
+// packages are hidden from the output by default. eris.SetFrameFilters
+// replaces these filters (calling it without any filters shows every frame),
+// and the Filters and ShowAllFrames fields of eris.FrameOptions, which is
+// embedded in each format, control filtering for a single format.
+//
+// Frames are named after their package, receiver type and function (e.g.
+// "api.(*Server).GetResource" or "api.GetResource (closure 1)"). The Package,
+// Receiver and Function fields of StackFrame hold these parts separately, and
+// FrameOptions.FullNames shows the full package path instead.
+//
+// Custom layouts can be implemented via the Formatter interface, which
+// writes an UnpackedError to an io.Writer. Format is a Formatter itself, and
+// eris.SetFormatter replaces the formatter used when errors are printed with
+// the %+v verb or with any other verb.
+//
+// eris.NewTemplateFormat returns a Formatter based on a text/template
+// template, and eris.DefaultTemplate and eris.DefaultTraceTemplate reproduce
+// the default output.
+//
+// eris.NewPrettyFormat returns a Formatter for reading errors in a terminal,
+// with colors and stack frame locations aligned in a column. Colors are only
+// used if the output is a terminal (detected as a character device) and
+// NO_COLOR isn't set.
+//
+// eris.QuickfixFormat prints stack frames in the style of compiler
+// diagnostics ("file:line: function"), which editors can jump to, optionally
+// with OSC 8 terminal hyperlinks.
+//
+// Structured fields
+//
+// Rather than formatting context such as user or request IDs into error
+// messages, it can be attached as structured fields via eris.WithFields.
+// Fields are merged across the whole chain and can be retrieved via
+// eris.Fields or eris.Unpack. They're also included in JSON output.
+//
+//    _, err := db.Get(id)
+//    if err != nil {
+//      err = eris.Wrap(err, "error getting resource")
+//      return eris.WithFields(err, map[string]interface{}{"resource": id})
+//    }
+//
+// Error codes
+//
+// eris.WithCode attaches a Code to an error. Codes mirror the canonical gRPC
+// status codes and can be translated to HTTP status codes, which avoids
+// matching on error messages when building API responses. eris.CodeOf
+// returns the outermost code in the chain.
+//
+//    NotFound := eris.WithCode(eris.New("not found"), eris.NotFound)
+//    ...
+//    http.Error(w, err.Error(), eris.CodeOf(err).HTTPStatus())
+//
+// Sending errors across services
+//
+// Errors and UnpackedError objects implement json.Marshaler, and
+// eris.FromJSON decodes the resulting JSON back into an error with the same
+// messages, stack traces, fields, and codes. Error identity can't be encoded,
+// so sentinel errors that should still match via eris.Is are passed to
+// FromJSON and matched by message.
+//
+//    data, _ := json.Marshal(eris.Wrap(ErrNotFound, "error getting resource"))
+//    ...
+//    err, _ := eris.FromJSON(data, ErrNotFound)
+//    eris.Is(err, ErrNotFound) // true
+//
 // Logging errors with more control
 //
 // While eris supports logging errors with Go's fmt package, it's often
@@ -115,18 +267,32 @@
 //        "error chain":[
 //          {
 //            "message":"error getting resource 'example-id'",
-//            "stack":"api.GetResource: /path/to/file/api.go: 30"
+//            "stack":"api.GetResource: api/api.go: 30"
 //          }
 //        ],
 //        "error root":{
 //          "message":"not found",
 //          "stack":[
-//            "api.GetResource: /path/to/file/api.go: 30",
-//            "db.Get: /path/to/file/db.go: 99",
-//            "runtime.goexit: /path/to/go/src/runtime/asm_amd64.s: 1337"
+//            "api.GetResource: api/api.go: 30",
+//            "db.Get: db/db.go: 99"
 //          ]
 //        }
 //      }
 //    }
 //
+// For log pipelines that need a stable structure, UnpackedError.ToJSONError
+// returns a typed JSONError instead. It has a version field, keys without
+// spaces, and structured stack frames, and it's described by the JSON Schema
+// document in eris.JSONSchema.
+//
+// With Go 1.21 or later, eris errors implement slog.LogValuer, so log/slog
+// logs them as a group with the same keys as JSONError, where lists such as
+// the chain and stack frames are groups keyed by their index. Stack traces
+// aren't included by default since symbolizing them on every log call is
+// expensive. eris.NewSlogHandler wraps a slog.Handler to expand errors of any
+// type and to control whether stack traces are logged.
+//
+//    logger := slog.New(eris.NewSlogHandler(slog.NewJSONHandler(os.Stderr, nil), true))
+//    logger.Error("method completed with error", "error", err)
+//
 package eris
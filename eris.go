@@ -2,8 +2,9 @@ package eris
 
 import (
 	"fmt"
-	"io"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 // New creates a new root error with a static message.
@@ -14,44 +15,164 @@ func New(msg string) error {
 	}
 }
 
+// NewWithDepth creates a new root error with a static message and a stack trace of at most depth frames. The
+// package-level maximum depth set via SetMaxStackDepth is used if depth is lower than 1.
+func NewWithDepth(msg string, depth int) error {
+	return &rootError{
+		msg:   msg,
+		stack: callersDepth(3, depth),
+	}
+}
+
 // Errorf creates a new root error with a formatted message.
+//
+// Like fmt.Errorf, Errorf supports the %w verb for error operands, which are formatted like %v and linked into the
+// chain so eris.Is and eris.As find them. If the format ends with a single ": %w" verb, the result is the same as
+// wrapping the operand via Wrapf with the rest of the format, which keeps stack traces and context of eris errors
+// intact. Otherwise, a root error is created that unwraps to the operand. If there are several %w verbs, or the
+// operand contains eris errors, the operands are joined as in eris.Join and show up as the branches of the root error
+// when it's unpacked, so their stack traces are kept as well. As with fmt.Errorf, %w verbs with an invalid argument
+// index or an operand that isn't an error don't wrap anything and are reported as bad verbs (e.g. "%!w(int=3)").
+//
+//	err := eris.Errorf("error reading %v: %w", path, err) // same as eris.Wrapf(err, "error reading %v", path)
 func Errorf(format string, args ...interface{}) error {
-	return &rootError{
-		msg:   fmt.Sprintf(format, args...),
+	vformat, verbs := parseWrapVerbs(format, args)
+	errs := wrappedErrors(verbs, args)
+	if len(verbs) == 1 && len(errs) == 1 && verbs[0].pos == len(format)-1 && verbs[0].arg == len(args)-1 &&
+		strings.HasSuffix(format, ": %w") {
+		return wrap(errs[0], fmt.Sprintf(format[:len(format)-len(": %w")], args[:len(args)-1]...), 0)
+	}
+
+	e := &rootError{
+		msg:   fmt.Sprintf(vformat, args...),
 		stack: callers(3),
 	}
+	switch {
+	case len(errs) > 1 || len(errs) == 1 && hasErisError(errs[0]):
+		e.ext = join(nil, errs)
+	case len(errs) == 1:
+		e.ext = errs[0]
+	}
+	return e
 }
 
 // Wrap adds additional context to all error types while maintaining the type of the original error.
 //
-// This method behaves differently for each error type. For root errors, the stack trace is reset to the current
-// callers which ensures traces are correct when using global/sentinel error values. Wrapped error types are simply
-// wrapped with the new context. For external types (i.e. something other than root or wrap errors), a new root
-// error is created for the original error and then it's wrapped with the additional context.
+// This method behaves differently for each error type. For root errors, a copy of the error is created with the stack
+// trace set to the current callers which ensures traces are correct when using global/sentinel error values. The
+// original root error is left untouched and remains in the chain, so eris.Is and eris.Cause still match it. Wrapped
+// error types are simply wrapped with the new context. For external types (i.e. something other than root or wrap
+// errors), a new root error is created for the original error and then it's wrapped with the additional context,
+// unless the external error itself wraps an eris error (e.g. via fmt.Errorf and the %w verb) that already carries a
+// stack trace.
 func Wrap(err error, msg string) error {
-	return wrap(err, msg)
+	return wrap(err, msg, 0)
+}
+
+// WrapWithDepth adds additional context to all error types while maintaining the type of the original error.
+//
+// This is the same as Wrap except that stack traces created for root and external errors contain at most depth
+// frames. The package-level maximum depth set via SetMaxStackDepth is used if depth is lower than 1.
+func WrapWithDepth(err error, msg string, depth int) error {
+	return wrap(err, msg, depth)
 }
 
 // Wrapf adds additional context to all error types while maintaining the type of the original error.
 //
-// This is a convenience method for wrapping errors with formatted messages and is otherwise the same as Wrap.
+// This is a convenience method for wrapping errors with formatted messages and is otherwise the same as Wrap. Error
+// operands of %w verbs in the format are formatted like %v, and eris.Is and eris.As also search them in addition to
+// err's chain.
 func Wrapf(err error, format string, args ...interface{}) error {
-	return wrap(err, fmt.Sprintf(format, args...))
+	vformat, verbs := parseWrapVerbs(format, args)
+	e := wrap(err, fmt.Sprintf(vformat, args...), 0)
+	if e != nil {
+		e.(*wrapError).ops = wrappedErrors(verbs, args)
+	}
+	return e
+}
+
+// Join returns an error that combines the given errors into a multi-error. Nil errors are discarded and nil is
+// returned if every error is nil.
+//
+// Each error becomes a separate branch of the resulting error tree. Root and external errors are prepared the same
+// way as in Wrap, so every branch carries its own stack trace.
+func Join(errs ...error) error {
+	return join(nil, errs)
+}
+
+// Append adds errs to err and returns the resulting multi-error. If err is already a multi-error created by Join or
+// Append, the errors are added as new branches of a copy of it, which keeps its fields and code. Otherwise, the result
+// is the same as calling Join(err, errs...).
+func Append(err error, errs ...error) error {
+	if e, ok := err.(*joinError); ok {
+		joined, ok := join(e.errs[:len(e.errs):len(e.errs)], errs).(*joinError)
+		if !ok {
+			return nil
+		}
+		joined.meta = e.meta
+		return joined
+	}
+	return join(nil, append([]error{err}, errs...))
+}
+
+func join(branches []error, errs []error) error {
+	var stack *stack
+	for _, err := range errs {
+		switch e := err.(type) {
+		case nil:
+			continue
+		case *rootError:
+			if stack == nil {
+				stack = callers(4)
+			}
+			err = e.copy(stack)
+		case *wrapError, *joinError:
+		default:
+			if hasErisError(e) {
+				break
+			}
+			extStack := externalStack(e, 0)
+			if extStack == nil {
+				if stack == nil {
+					stack = callers(4)
+				}
+				extStack = stack
+			}
+			err = &rootError{
+				msg:   e.Error(),
+				ext:   e,
+				stack: extStack,
+			}
+		}
+		branches = append(branches, err)
+	}
+	if len(branches) == 0 {
+		return nil
+	}
+	return &joinError{errs: branches}
 }
 
-func wrap(err error, msg string) error {
+func wrap(err error, msg string, depth int) error {
 	if err == nil {
 		return nil
 	}
 
 	switch e := err.(type) {
 	case *rootError:
-		e.stack = callers(4)
-	case *wrapError:
+		err = e.copy(callersDepth(4, depth))
+	case *wrapError, *joinError:
 	default:
+		if hasErisError(e) {
+			break
+		}
+		stack := externalStack(e, depth)
+		if stack == nil {
+			stack = callersDepth(4, depth)
+		}
 		err = &rootError{
 			msg:   e.Error(),
-			stack: callers(4),
+			ext:   e,
+			stack: stack,
 		}
 	}
 
@@ -62,6 +183,182 @@ func wrap(err error, msg string) error {
 	}
 }
 
+// wrapVerb is a %w verb in a format string.
+type wrapVerb struct {
+	pos int // position of the verb character
+	arg int // index of the operand
+}
+
+// parseWrapVerbs returns the given format with the %w verbs of error operands replaced by %v, along with the positions
+// and operands of the replaced verbs. Operands are counted the same way as by the fmt package, including explicit
+// argument indexes and operands for * widths and precisions. Like fmt.Errorf, verbs with an invalid argument index, a
+// missing operand, or an operand that isn't an error are left as they are, so fmt reports them as bad verbs (e.g.
+// "%!w(BADINDEX)" or "%!w(int=3)").
+func parseWrapVerbs(format string, args []interface{}) (string, []wrapVerb) {
+	if !strings.Contains(format, "%") {
+		return format, nil
+	}
+	var (
+		buf   []byte
+		verbs []wrapVerb
+		arg   int
+		good  bool // whether the argument indexes of the current verb are valid
+	)
+	// argIndex parses an explicit argument index such as "[2]" at position i and returns the position following it.
+	argIndex := func(i int) int {
+		if i >= len(format) || format[i] != '[' {
+			return i
+		}
+		end := strings.IndexByte(format[i:], ']')
+		if end < 0 {
+			good = false
+			return i + 1
+		}
+		index := format[i+1 : i+end]
+		n, err := strconv.Atoi(index)
+		if err != nil || strings.ContainsAny(index, "+-") || n < 1 || n > len(args) {
+			good = false
+		} else {
+			arg = n - 1
+		}
+		return i + end + 1
+	}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		good = true
+		for i++; i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0; i++ {
+		}
+		i = argIndex(i)
+		if i < len(format) && format[i] == '*' {
+			i, arg = i+1, arg+1
+		}
+		for ; i < len(format) && format[i] >= '0' && format[i] <= '9'; i++ {
+		}
+		if i < len(format) && format[i] == '.' {
+			i = argIndex(i + 1)
+			if i < len(format) && format[i] == '*' {
+				i, arg = i+1, arg+1
+			}
+			for ; i < len(format) && format[i] >= '0' && format[i] <= '9'; i++ {
+			}
+		}
+		i = argIndex(i)
+		if i >= len(format) || format[i] == '%' {
+			continue
+		}
+		if !good {
+			// fmt doesn't use an operand for verbs with an invalid argument index
+			continue
+		}
+		if format[i] == 'w' && arg < len(args) {
+			if err, ok := args[arg].(error); ok && err != nil {
+				if buf == nil {
+					buf = []byte(format)
+				}
+				buf[i] = 'v'
+				verbs = append(verbs, wrapVerb{pos: i, arg: arg})
+			}
+		}
+		arg++
+	}
+	if buf == nil {
+		return format, nil
+	}
+	return string(buf), verbs
+}
+
+// wrappedErrors returns the error operands of the given %w verbs.
+func wrappedErrors(verbs []wrapVerb, args []interface{}) []error {
+	var errs []error
+	for _, verb := range verbs {
+		errs = append(errs, args[verb.arg].(error))
+	}
+	return errs
+}
+
+// WithFields attaches structured key/value fields to err. The fields are merged with any fields already attached to
+// err, overwriting existing values for the same keys. The original error is never modified.
+//
+// Fields are meant for machine-readable context such as user or request IDs. They can be retrieved via eris.Fields
+// and eris.Unpack, and they're included in the output of UnpackedError.ToJSON. Like Wrap, external errors are turned
+// into root errors first and root errors are copied with the stack trace set to the current callers.
+func WithFields(err error, fields map[string]interface{}) error {
+	return annotate(err, func(m *meta) {
+		m.fields = mergeFields(m.fields, fields)
+	})
+}
+
+// Fields returns the structured fields attached to every error in err's chain, merged into a single map. Fields
+// attached to outer errors take precedence over fields with the same key further down the chain. The branches of
+// multi-errors are not included. Nil is returned if the chain doesn't contain any fields.
+func Fields(err error) map[string]interface{} {
+	var layers []map[string]interface{}
+	for ; err != nil; err = Unwrap(err) {
+		switch e := err.(type) {
+		case *rootError:
+			layers = append(layers, e.fields)
+		case *wrapError:
+			layers = append(layers, e.fields)
+		case *joinError:
+			layers = append(layers, e.fields)
+		}
+	}
+	var fields map[string]interface{}
+	for i := len(layers) - 1; i >= 0; i-- {
+		if len(layers[i]) > 0 {
+			fields = mergeFields(fields, layers[i])
+		}
+	}
+	return fields
+}
+
+// annotate returns a copy of err with its metadata modified by update. Root errors are copied with the stack trace
+// set to the callers of the exported function that called annotate, and external errors are turned into root errors.
+func annotate(err error, update func(m *meta)) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *rootError:
+		c := e.copy(callers(4))
+		update(&c.meta)
+		return c
+	case *wrapError:
+		c := *e
+		update(&c.meta)
+		return &c
+	case *joinError:
+		c := *e
+		update(&c.meta)
+		return &c
+	default:
+		stack := externalStack(e, 0)
+		if stack == nil {
+			stack = callers(4)
+		}
+		c := &rootError{
+			msg:   e.Error(),
+			ext:   e,
+			stack: stack,
+		}
+		update(&c.meta)
+		return c
+	}
+}
+
+// mergeFields returns a new map containing the fields of a overwritten by the fields of b.
+func mergeFields(a, b map[string]interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(a)+len(b))
+	for k, v := range a {
+		fields[k] = v
+	}
+	for k, v := range b {
+		fields[k] = v
+	}
+	return fields
+}
+
 // Unwrap returns the result of calling the Unwrap method on err, if err's type contains an Unwrap method
 // returning error. Otherwise, Unwrap returns nil.
 func Unwrap(err error) error {
@@ -74,18 +371,37 @@ func Unwrap(err error) error {
 	return u.Unwrap()
 }
 
+// unwrapCause returns the error wrapped by err via `Unwrap() error`, or via `Cause() error` for errors created by
+// packages such as github.com/pkg/errors that predate Unwrap.
+func unwrapCause(err error) error {
+	if u, ok := err.(interface{ Unwrap() error }); ok {
+		return u.Unwrap()
+	}
+	if c, ok := err.(interface{ Cause() error }); ok {
+		if cause := c.Cause(); cause != err {
+			return cause
+		}
+	}
+	return nil
+}
+
 // Is reports whether any error in err's chain matches target.
 //
-// The chain consists of err itself followed by the sequence of errors obtained by repeatedly calling Unwrap.
+// The chain consists of err itself followed by the sequence of errors obtained by repeatedly calling Unwrap. If an
+// error in the chain is a multi-error (i.e. it implements `Unwrap() []error`), each of its branches is searched.
 //
 // An error is considered to match a target if it is equal to that target or if it implements a method
-// Is(error) bool such that Is(target) returns true.
+// Is(error) bool such that Is(target) returns true. Errors are matched by identity rather than by message: a root
+// error only matches itself, even after it's been wrapped, so two unrelated errors with the same message are never
+// considered equal. Use eris.IsMessage to match errors by message instead.
 func Is(err, target error) bool {
 	if target == nil {
 		return err == target
 	}
+	return is(err, target, reflect.TypeOf(target).Comparable())
+}
 
-	isComparable := reflect.TypeOf(target).Comparable()
+func is(err, target error, isComparable bool) bool {
 	for {
 		if isComparable && err == target {
 			return true
@@ -93,17 +409,135 @@ func Is(err, target error) bool {
 		if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
 			return true
 		}
+		if x, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, branch := range x.Unwrap() {
+				if branch != nil && is(branch, target, isComparable) {
+					return true
+				}
+			}
+			return false
+		}
 		if err = Unwrap(err); err == nil {
 			return false
 		}
 	}
 }
 
+// IsMessage reports whether any error in err's chain has the same message as target.
+//
+// This is how eris.Is behaved in earlier versions of this package and is provided for backwards compatibility. The
+// message of each root and wrap error in the chain is compared separately, so target can match any layer of context.
+// For eris errors, the target message excludes the messages of the errors it wraps. Prefer eris.Is for comparisons
+// against sentinel errors.
+func IsMessage(err, target error) bool {
+	if target == nil {
+		return err == target
+	}
+	msg := target.Error()
+	switch t := target.(type) {
+	case *rootError:
+		msg = t.msg
+	case *wrapError:
+		msg = t.msg
+	}
+	return isMessage(err, msg)
+}
+
+func isMessage(err error, msg string) bool {
+	for ; err != nil; err = Unwrap(err) {
+		switch e := err.(type) {
+		case *rootError:
+			if e.msg == msg {
+				return true
+			}
+		case *wrapError:
+			if e.msg == msg {
+				return true
+			}
+		case interface{ Unwrap() []error }:
+			for _, branch := range e.Unwrap() {
+				if isMessage(branch, msg) {
+					return true
+				}
+			}
+			return false
+		default:
+			if e.Error() == msg {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// As finds the first error in err's chain that matches target, and if so, sets target to that error value and
+// returns true. Otherwise, it returns false.
+//
+// The chain consists of err itself followed by the sequence of errors obtained by repeatedly calling Unwrap. External
+// errors wrapped by eris are part of the chain, so their original values can be extracted after wrapping. The
+// branches of multi-errors are searched in order.
+//
+// An error matches target if the error's concrete value is assignable to the value pointed to by target, or if the
+// error has a method As(interface{}) bool such that As(target) returns true.
+//
+// As panics if target is not a non-nil pointer to either a type that implements error, or to any interface type.
+func As(err error, target interface{}) bool {
+	if target == nil {
+		panic("eris: target cannot be nil")
+	}
+	val := reflect.ValueOf(target)
+	typ := val.Type()
+	if typ.Kind() != reflect.Ptr || val.IsNil() {
+		panic("eris: target must be a non-nil pointer")
+	}
+	targetType := typ.Elem()
+	if targetType.Kind() != reflect.Interface && !targetType.Implements(errorType) {
+		panic("eris: *target must be interface or implement error")
+	}
+	return as(err, target, val, targetType)
+}
+
+func as(err error, target interface{}, val reflect.Value, targetType reflect.Type) bool {
+	for err != nil {
+		if reflect.TypeOf(err).AssignableTo(targetType) {
+			val.Elem().Set(reflect.ValueOf(err))
+			return true
+		}
+		if x, ok := err.(interface{ As(interface{}) bool }); ok && x.As(target) {
+			return true
+		}
+		if x, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, branch := range x.Unwrap() {
+				if as(branch, target, val, targetType) {
+					return true
+				}
+			}
+			return false
+		}
+		err = Unwrap(err)
+	}
+	return false
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
 // Cause returns the root cause of the error, which is defined as the first error in the chain. The original
-// error is returned if it does not implement `Unwrap() error` and nil is returned if the error is nil.
+// error is returned if it does not implement `Unwrap() error` and nil is returned if the error is nil. For
+// multi-errors, Cause follows the first non-nil branch. Errors that implement `Cause() error` instead of Unwrap, such
+// as the errors of github.com/pkg/errors, are followed as well.
 func Cause(err error) error {
 	for {
-		uerr := Unwrap(err)
+		var uerr error
+		if x, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, branch := range x.Unwrap() {
+				if branch != nil {
+					uerr = branch
+					break
+				}
+			}
+		} else {
+			uerr = unwrapCause(err)
+		}
 		if uerr == nil {
 			return err
 		}
@@ -111,11 +545,31 @@ func Cause(err error) error {
 	}
 }
 
+// meta holds the metadata that can be attached to any eris error.
+type meta struct {
+	fields map[string]interface{}
+	code   Code
+}
+
 type rootError struct {
-	msg   string
+	msg      string
+	ext      error      // original external error, if any
+	sentinel *rootError // root error this one was copied from, if any
+	meta
 	stack *stack
 }
 
+// copy returns a new root error with the given stack trace that unwraps to the original root error. Root errors are
+// never modified after creation, which keeps global/sentinel errors safe for concurrent use.
+func (e *rootError) copy(stack *stack) *rootError {
+	c := *e
+	if c.sentinel == nil {
+		c.sentinel = e
+	}
+	c.stack = stack
+	return &c
+}
+
 func (e *rootError) Error() string {
 	return fmt.Sprint(e)
 }
@@ -124,16 +578,41 @@ func (e *rootError) Format(s fmt.State, verb rune) {
 	printError(e, s, verb)
 }
 
+// Is reports whether target is the same root error as e. Copies of a root error created while wrapping it or attaching
+// metadata to it share the identity of the original error.
 func (e *rootError) Is(target error) bool {
 	if err, ok := target.(*rootError); ok {
-		return e.msg == err.msg
+		return e.origin() == err.origin()
+	}
+	return false
+}
+
+// origin returns the root error that e was originally copied from.
+func (e *rootError) origin() *rootError {
+	if e.sentinel != nil {
+		return e.sentinel
+	}
+	return e
+}
+
+// StackTrace returns the program counters of the error's stack trace. Error reporters such as the Sentry SDK detect
+// this method the same way as the StackTrace method of github.com/pkg/errors errors.
+func (e *rootError) StackTrace() []uintptr {
+	return StackPCs(e)
+}
+
+func (e *rootError) Unwrap() error {
+	if e.sentinel != nil {
+		return e.sentinel
 	}
-	return e.msg == target.Error()
+	return e.ext
 }
 
 type wrapError struct {
-	msg   string
-	err   error
+	msg string
+	err error
+	ops []error // additional errors wrapped via %w verbs in Wrapf
+	meta
 	frame *frame
 }
 
@@ -145,15 +624,57 @@ func (e *wrapError) Format(s fmt.State, verb rune) {
 	printError(e, s, verb)
 }
 
+func (e *wrapError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace returns the program counters of the stack trace of the root error beneath the wrap error, which is the
+// most complete stack trace in the chain. See rootError.StackTrace.
+func (e *wrapError) StackTrace() []uintptr {
+	return StackPCs(e)
+}
+
+// Is reports whether any of the errors wrapped via %w verbs in Wrapf matches target.
 func (e *wrapError) Is(target error) bool {
-	if err, ok := target.(*wrapError); ok {
-		return e.msg == err.msg
+	for _, op := range e.ops {
+		if Is(op, target) {
+			return true
+		}
 	}
-	return e.msg == target.Error()
+	return false
 }
 
-func (e *wrapError) Unwrap() error {
-	return e.err
+// As reports whether any of the errors wrapped via %w verbs in Wrapf matches target and sets target to it.
+func (e *wrapError) As(target interface{}) bool {
+	for _, op := range e.ops {
+		if As(op, target) {
+			return true
+		}
+	}
+	return false
+}
+
+type joinError struct {
+	errs []error
+	meta
+}
+
+func (e *joinError) Error() string {
+	return fmt.Sprint(e)
+}
+
+func (e *joinError) Format(s fmt.State, verb rune) {
+	printError(e, s, verb)
+}
+
+// StackTrace returns the program counters of the stack trace of the first branch with a stack trace. See
+// rootError.StackTrace.
+func (e *joinError) StackTrace() []uintptr {
+	return StackPCs(e)
+}
+
+func (e *joinError) Unwrap() []error {
+	return e.errs
 }
 
 func printError(err error, s fmt.State, verb rune) {
@@ -164,8 +685,5 @@ func printError(err error, s fmt.State, verb rune) {
 			withTrace = true
 		}
 	}
-	format := NewDefaultFormat(withTrace)
-	uErr := Unpack(err)
-	str := uErr.ToString(format)
-	_, _ = io.WriteString(s, str)
+	_ = formatter(withTrace).FormatError(s, unpack(err, withTrace))
 }
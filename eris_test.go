@@ -1,8 +1,14 @@
 package eris_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/rotisserie/eris"
@@ -48,6 +54,11 @@ func TestErrorWrapping(t *testing.T) {
 			cause:  errors.New("external error"),
 			output: "external error",
 		},
+		"error wrapping with external error in the chain (fmt.Errorf)": {
+			cause:  fmt.Errorf("external context: %w", eris.New("root error")),
+			input:  []string{"additional context", "even more context"},
+			output: "even more context: additional context: external context: root error",
+		},
 	}
 
 	for desc, tc := range tests {
@@ -99,70 +110,259 @@ func TestErrorUnwrap(t *testing.T) {
 	}
 }
 
+func TestErrorfWrapping(t *testing.T) {
+	globalErr := eris.New("global error")
+	otherErr := eris.New("other error")
+	pathErr := &os.PathError{Op: "open", Path: "/tmp/missing", Err: os.ErrNotExist}
+
+	tests := map[string]struct {
+		input    error    // error created via eris.Errorf or eris.Wrapf
+		output   string   // expected output
+		chain    []string // expected messages of the unpacked error chain
+		branches []string // expected messages of the unpacked branches
+		is       []error  // errors expected to match via eris.Is and errors.Is
+		isNot    []error  // errors expected not to match via eris.Is and errors.Is
+		as       bool     // whether the input is expected to contain pathErr
+	}{
+		"errorf without error operands": {
+			input:  eris.Errorf("%v: %d", "root error", 1),
+			output: "root error: 1",
+		},
+		"errorf with trailing %w": {
+			input:  eris.Errorf("error reading %v: %w", "/tmp/missing", globalErr),
+			output: "error reading /tmp/missing: global error",
+			chain:  []string{"error reading /tmp/missing"},
+			is:     []error{globalErr},
+		},
+		"errorf with trailing %w for an external error": {
+			input:  eris.Errorf("error reading %v: %w", "/tmp/missing", pathErr),
+			output: "error reading /tmp/missing: open /tmp/missing: file does not exist",
+			chain:  []string{"error reading /tmp/missing"},
+			is:     []error{pathErr, os.ErrNotExist},
+			as:     true,
+		},
+		"errorf with %w in the middle": {
+			input:  eris.Errorf("reading (%w) failed", pathErr),
+			output: "reading (open /tmp/missing: file does not exist) failed",
+			is:     []error{pathErr, os.ErrNotExist},
+			as:     true,
+		},
+		"errorf with eris error in the middle": {
+			input:    eris.Errorf("reading (%w) failed", globalErr),
+			output:   "reading (global error) failed",
+			branches: []string{"global error"},
+			is:       []error{globalErr},
+		},
+		"errorf with multiple %w": {
+			input:    eris.Errorf("%[2]w, then %[1]*[3]w", 5, globalErr, pathErr),
+			output:   "global error, then open /tmp/missing: file does not exist",
+			branches: []string{"global error", "open /tmp/missing: file does not exist"},
+			is:       []error{globalErr, pathErr},
+			as:       true,
+		},
+		"errorf with multiple eris errors": {
+			input:    eris.Errorf("first %w, then %w", globalErr, otherErr),
+			output:   "first global error, then other error",
+			branches: []string{"global error", "other error"},
+			is:       []error{globalErr, otherErr},
+		},
+		"errorf with a malformed index": {
+			input:  eris.Errorf("%[x]w %v", globalErr, otherErr),
+			output: "%!w(BADINDEX) global error",
+			isNot:  []error{globalErr, otherErr},
+		},
+		"errorf with an index out of range": {
+			input:  eris.Errorf("%[3]w", globalErr, otherErr),
+			output: "%!w(BADINDEX)",
+			isNot:  []error{globalErr, otherErr},
+		},
+		"errorf with a non-error operand": {
+			input:  eris.Errorf("%[3]w", globalErr, otherErr, 3),
+			output: "%!w(int=3)",
+			isNot:  []error{globalErr, otherErr},
+		},
+		"errorf with a missing operand": {
+			input:  eris.Errorf("%v: %w", "root error"),
+			output: "root error: %!w(MISSING)",
+		},
+		"wrapf with %w": {
+			input:  eris.Wrapf(globalErr, "error reading (%w)", pathErr),
+			output: "error reading (open /tmp/missing: file does not exist): global error",
+			chain:  []string{"error reading (open /tmp/missing: file does not exist)"},
+			is:     []error{globalErr, pathErr, os.ErrNotExist},
+			as:     true,
+		},
+	}
+
+	for desc, tc := range tests {
+		if tc.input.Error() != tc.output {
+			t.Errorf("%v: expected { %v } got { %v }", desc, tc.output, tc.input)
+		}
+		uErr := eris.Unpack(tc.input)
+		var chain []string
+		if uErr.ErrChain != nil {
+			for _, link := range *uErr.ErrChain {
+				chain = append(chain, link.Msg)
+				if link.Frame.Name != "eris_test.TestErrorfWrapping" {
+					t.Errorf("%v: unexpected frame { %v }", desc, link.Frame.Name)
+				}
+			}
+		}
+		if !reflect.DeepEqual(chain, tc.chain) {
+			t.Errorf("%v: expected chain { %v } got { %v }", desc, tc.chain, chain)
+		}
+		if uErr.ErrRoot == nil || uErr.ErrRoot.Stack[0].Name != "eris_test.TestErrorfWrapping" {
+			t.Errorf("%v: expected a stack trace starting in the test function { %+v }", desc, uErr.ErrRoot)
+		}
+		var branches []string
+		for _, branch := range uErr.ErrBranches {
+			branches = append(branches, branch.ToString(eris.NewDefaultFormat(false)))
+			if branch.ErrRoot == nil || len(branch.ErrRoot.Stack) == 0 {
+				t.Errorf("%v: expected branch with stack trace { %+v }", desc, branch)
+			}
+		}
+		if !reflect.DeepEqual(branches, tc.branches) {
+			t.Errorf("%v: expected branches { %v } got { %v }", desc, tc.branches, branches)
+		}
+		trace := fmt.Sprintf("%+v", tc.input)
+		if len(tc.branches) > 0 && !strings.Contains(trace, "\t"+tc.branches[0]+"\n") {
+			t.Errorf("%v: expected the branches in the trace { %v }", desc, trace)
+		}
+		for _, target := range tc.is {
+			if !eris.Is(tc.input, target) || !errors.Is(tc.input, target) {
+				t.Errorf("%v: expected { %v } to match { %v }", desc, tc.input, target)
+			}
+		}
+		for _, target := range tc.isNot {
+			if eris.Is(tc.input, target) || errors.Is(tc.input, target) {
+				t.Errorf("%v: expected { %v } not to match { %v }", desc, tc.input, target)
+			}
+		}
+		var target *os.PathError
+		if ok := eris.As(tc.input, &target); ok != tc.as || ok && target != pathErr {
+			t.Errorf("%v: expected eris.As to return %v but got %v (%v)", desc, tc.as, ok, target)
+		}
+
+		// the branches are kept when the error is sent across services
+		data, err := json.Marshal(uErr)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", desc, err)
+		}
+		decoded, err := eris.FromJSON(data, globalErr, otherErr)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", desc, err)
+		}
+		if decoded.Error() != tc.output {
+			t.Errorf("%v: expected decoded error { %v } got { %v }", desc, tc.output, decoded)
+		}
+		if decodedData, _ := json.Marshal(eris.Unpack(decoded)); string(decodedData) != string(data) {
+			t.Errorf("%v: expected decoded error { %s } got { %s }", desc, data, decodedData)
+		}
+		for _, target := range []error{globalErr, otherErr} {
+			if eris.Is(decoded, target) != eris.Is(tc.input, target) {
+				t.Errorf("%v: expected eris.Is(decoded, { %v }) to be %v", desc, target, eris.Is(tc.input, target))
+			}
+		}
+	}
+}
+
 func TestErrorIs(t *testing.T) {
 	globalErr := eris.New("global error")
+	externalErr := errors.New("external error")
 
 	tests := map[string]struct {
-		cause   error    // root error
-		input   []string // input for error wrapping
-		compare error    // errors for comparison
-		output  bool     // expected comparison result
+		cause     error    // root error
+		input     []string // input for error wrapping
+		compare   error    // errors for comparison
+		output    bool     // expected comparison result
+		msgOutput bool     // expected comparison result when matching by message
 	}{
 		"root error (internal)": {
-			cause:   eris.New("root error"),
-			input:   []string{"additional context", "even more context"},
-			compare: eris.New("root error"),
-			output:  true,
+			cause:     eris.New("root error"),
+			input:     []string{"additional context", "even more context"},
+			compare:   eris.New("root error"),
+			output:    false,
+			msgOutput: true,
 		},
 		"error not in chain": {
-			cause:   eris.New("root error"),
-			compare: eris.New("other error"),
-			output:  false,
+			cause:     eris.New("root error"),
+			compare:   eris.New("other error"),
+			output:    false,
+			msgOutput: false,
 		},
 		"middle of chain (internal)": {
-			cause:   eris.New("root error"),
-			input:   []string{"additional context", "even more context"},
-			compare: eris.New("additional context"),
-			output:  true,
+			cause:     eris.New("root error"),
+			input:     []string{"additional context", "even more context"},
+			compare:   eris.New("additional context"),
+			output:    false,
+			msgOutput: true,
 		},
 		"another in middle of chain (internal)": {
-			cause:   eris.New("root error"),
-			input:   []string{"additional context", "even more context"},
-			compare: eris.New("even more context"),
-			output:  true,
+			cause:     eris.New("root error"),
+			input:     []string{"additional context", "even more context"},
+			compare:   eris.New("even more context"),
+			output:    false,
+			msgOutput: true,
 		},
 		"root error (external)": {
-			cause:   errors.New("external error"),
-			input:   []string{"additional context", "even more context"},
-			compare: eris.New("external error"),
-			output:  true,
+			cause:     errors.New("external error"),
+			input:     []string{"additional context", "even more context"},
+			compare:   eris.New("external error"),
+			output:    false,
+			msgOutput: true,
+		},
+		"global root error": {
+			cause:     globalErr,
+			input:     []string{"additional context", "even more context"},
+			compare:   globalErr,
+			output:    true,
+			msgOutput: true,
+		},
+		"global root error with the same message": {
+			cause:     globalErr,
+			input:     []string{"additional context", "even more context"},
+			compare:   eris.New("global error"),
+			output:    false,
+			msgOutput: true,
 		},
 		"wrapped error from global root error": {
-			cause:   globalErr,
-			input:   []string{"additional context", "even more context"},
-			compare: eris.Wrap(globalErr, "additional context"),
-			output:  true,
+			cause:     globalErr,
+			input:     []string{"additional context", "even more context"},
+			compare:   eris.Wrap(globalErr, "additional context"),
+			output:    false,
+			msgOutput: true,
+		},
+		"global external error": {
+			cause:     externalErr,
+			input:     []string{"additional context", "even more context"},
+			compare:   externalErr,
+			output:    true,
+			msgOutput: true,
 		},
 		"comparing against external error": {
-			cause:   errors.New("external error"),
-			input:   []string{"additional context", "even more context"},
-			compare: errors.New("external error"),
-			output:  true,
+			cause:     errors.New("external error"),
+			input:     []string{"additional context", "even more context"},
+			compare:   errors.New("external error"),
+			output:    false,
+			msgOutput: true,
 		},
 		"comparing against nil error": {
-			cause:   eris.New("root error"),
-			compare: nil,
-			output:  false,
+			cause:     eris.New("root error"),
+			compare:   nil,
+			output:    false,
+			msgOutput: false,
 		},
 		"comparing error against itself": {
-			cause:   globalErr,
-			compare: globalErr,
-			output:  true,
+			cause:     globalErr,
+			compare:   globalErr,
+			output:    true,
+			msgOutput: true,
 		},
 		"comparing two nil errors": {
-			cause:   nil,
-			compare: nil,
-			output:  true,
+			cause:     nil,
+			compare:   nil,
+			output:    true,
+			msgOutput: true,
 		},
 	}
 
@@ -173,6 +373,238 @@ func TestErrorIs(t *testing.T) {
 		} else if !tc.output && eris.Is(err, tc.compare) {
 			t.Errorf("%v: expected eris.Is('%v', '%v') to return false but got true", desc, err, tc.compare)
 		}
+		if tc.msgOutput && !eris.IsMessage(err, tc.compare) {
+			t.Errorf("%v: expected eris.IsMessage('%v', '%v') to return true but got false", desc, err, tc.compare)
+		} else if !tc.msgOutput && eris.IsMessage(err, tc.compare) {
+			t.Errorf("%v: expected eris.IsMessage('%v', '%v') to return false but got true", desc, err, tc.compare)
+		}
+	}
+}
+
+func TestErrorAs(t *testing.T) {
+	pathErr := &os.PathError{Op: "open", Path: "/tmp/missing", Err: os.ErrNotExist}
+
+	tests := map[string]struct {
+		cause  error    // root error
+		input  []string // input for error wrapping
+		output error    // expected value of target (nil if As should return false)
+	}{
+		"external error without wrapping": {
+			cause:  pathErr,
+			output: pathErr,
+		},
+		"wrapped external error": {
+			cause:  pathErr,
+			input:  []string{"additional context", "even more context"},
+			output: pathErr,
+		},
+		"external error wrapped by an external error": {
+			cause:  fmt.Errorf("external context: %w", pathErr),
+			input:  []string{"additional context"},
+			output: pathErr,
+		},
+		"internal root error": {
+			cause: eris.New("root error"),
+			input: []string{"additional context"},
+		},
+		"nil error": {
+			cause: nil,
+		},
+	}
+
+	for desc, tc := range tests {
+		err := setupTestCase(false, tc.cause, tc.input)
+		var target *os.PathError
+		if ok := eris.As(err, &target); ok != (tc.output != nil) {
+			t.Errorf("%v: expected eris.As('%v') to return %v but got %v", desc, err, tc.output != nil, ok)
+		} else if ok && target != tc.output {
+			t.Errorf("%v: expected target { %v } got { %v }", desc, tc.output, target)
+		}
+		if ok := errors.As(err, &target); ok != (tc.output != nil) {
+			t.Errorf("%v: expected errors.As('%v') to return %v but got %v", desc, err, tc.output != nil, ok)
+		}
+	}
+}
+
+func TestErrorAsInterface(t *testing.T) {
+	err := eris.Wrap(&os.PathError{Op: "open", Path: "/tmp/missing", Err: os.ErrNotExist}, "additional context")
+	var target interface{ Timeout() bool }
+	if !eris.As(err, &target) {
+		t.Errorf("expected eris.As('%v') to find an error implementing Timeout", err)
+	}
+}
+
+func TestGlobalErrorWrapping(t *testing.T) {
+	globalErr := eris.New("global error")
+	globalStack := eris.Unpack(globalErr).ErrRoot.Stack
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = eris.Wrap(globalErr, "additional context")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if !eris.Is(err, globalErr) || eris.Cause(err) != globalErr {
+			t.Errorf("expected { %v } to be caused by the global error", err)
+		}
+		if stack := eris.Unpack(err).ErrRoot.Stack; reflect.DeepEqual(stack, globalStack) {
+			t.Errorf("expected wrapped error to have its own stack trace but got the global one { %v }", stack)
+		}
+	}
+	if stack := eris.Unpack(globalErr).ErrRoot.Stack; !reflect.DeepEqual(stack, globalStack) {
+		t.Errorf("expected global error stack { %v } to be unchanged but got { %v }", globalStack, stack)
+	}
+}
+
+func TestErrorJoin(t *testing.T) {
+	globalErr := eris.New("global error")
+	extErr := errors.New("external error")
+
+	tests := map[string]struct {
+		errs   []error // errors to join
+		output string  // expected output
+		is     []error // errors expected to be found in the tree
+		cause  error   // expected cause
+	}{
+		"nil errors": {
+			errs: []error{nil, nil},
+		},
+		"single error": {
+			errs:   []error{globalErr},
+			output: "global error",
+			is:     []error{globalErr},
+			cause:  globalErr,
+		},
+		"multiple errors": {
+			errs:   []error{nil, eris.Wrap(globalErr, "additional context"), extErr},
+			output: "additional context: global error; external error",
+			is:     []error{globalErr, extErr},
+			cause:  globalErr,
+		},
+		"nested multi-error": {
+			errs:   []error{extErr, eris.Join(eris.New("root error"), globalErr)},
+			output: "external error; root error; global error",
+			is:     []error{globalErr, extErr},
+			cause:  extErr,
+		},
+	}
+
+	for desc, tc := range tests {
+		err := eris.Join(tc.errs...)
+		if tc.output == "" {
+			if err != nil {
+				t.Errorf("%v: joining nil errors should return nil but got { %v }", desc, err)
+			}
+			continue
+		}
+		if tc.output != err.Error() {
+			t.Errorf("%v: expected { %v } got { %v }", desc, tc.output, err)
+		}
+		for _, target := range tc.is {
+			if !eris.Is(err, target) {
+				t.Errorf("%v: expected eris.Is('%v', '%v') to return true but got false", desc, err, target)
+			}
+		}
+		if cause := eris.Cause(err); cause != tc.cause {
+			t.Errorf("%v: expected cause { %v } got { %v }", desc, tc.cause, cause)
+		}
+	}
+}
+
+func TestErrorAppend(t *testing.T) {
+	var err error
+	for _, msg := range []string{"first error", "second error", "third error"} {
+		err = eris.Append(err, eris.New(msg))
+	}
+	err = eris.Wrap(err, "additional context")
+	if expected := "additional context: first error; second error; third error"; err.Error() != expected {
+		t.Errorf("expected { %v } got { %v }", expected, err)
+	}
+
+	var pathErr *os.PathError
+	err = eris.Append(err, nil, &os.PathError{Op: "open", Path: "/tmp/missing", Err: os.ErrNotExist})
+	if !eris.As(err, &pathErr) {
+		t.Errorf("expected eris.As('%v') to find the appended error", err)
+	}
+	if branches := len(eris.Unpack(err).ErrBranches); branches != 2 {
+		t.Errorf("expected appending to a wrapped multi-error to create 2 branches but got %v", branches)
+	}
+	if err := eris.Append(nil, nil); err != nil {
+		t.Errorf("appending nil errors should return nil but got { %v }", err)
+	}
+
+	joined := eris.WithFields(eris.Join(eris.New("first error")), map[string]interface{}{"key": 1})
+	joined = eris.WithCode(joined, eris.NotFound)
+	err = eris.Append(joined, io.EOF)
+	if fields := eris.Fields(err); !reflect.DeepEqual(fields, map[string]interface{}{"key": 1}) {
+		t.Errorf("expected appending to keep the fields of the multi-error but got { %v }", fields)
+	}
+	if code := eris.CodeOf(err); code != eris.NotFound {
+		t.Errorf("expected appending to keep the code of the multi-error but got { %v }", code)
+	}
+}
+
+func TestErrorFields(t *testing.T) {
+	globalErr := eris.New("global error")
+
+	tests := map[string]struct {
+		err    error                  // error with fields
+		output map[string]interface{} // expected fields
+	}{
+		"nil error": {
+			err: eris.WithFields(nil, map[string]interface{}{"user": "alice"}),
+		},
+		"error without fields": {
+			err: eris.Wrap(globalErr, "additional context"),
+		},
+		"root error with fields": {
+			err:    eris.WithFields(globalErr, map[string]interface{}{"user": "alice"}),
+			output: map[string]interface{}{"user": "alice"},
+		},
+		"external error with fields": {
+			err:    eris.WithFields(errors.New("external error"), map[string]interface{}{"user": "alice"}),
+			output: map[string]interface{}{"user": "alice"},
+		},
+		"fields merged across the chain": {
+			err: eris.WithFields(
+				eris.Wrap(
+					eris.WithFields(globalErr, map[string]interface{}{"user": "alice", "request": 1}),
+					"additional context",
+				),
+				map[string]interface{}{"request": 2, "resource": "example-id"},
+			),
+			output: map[string]interface{}{"user": "alice", "request": 2, "resource": "example-id"},
+		},
+		"fields on a multi-error": {
+			err: eris.WithFields(
+				eris.Join(eris.WithFields(globalErr, map[string]interface{}{"user": "alice"})),
+				map[string]interface{}{"request": 1},
+			),
+			output: map[string]interface{}{"request": 1},
+		},
+	}
+
+	for desc, tc := range tests {
+		if fields := eris.Fields(tc.err); !reflect.DeepEqual(fields, tc.output) {
+			t.Errorf("%v: expected fields { %v } got { %v }", desc, tc.output, fields)
+		}
+		if fields := eris.Unpack(tc.err).Fields; !reflect.DeepEqual(fields, tc.output) {
+			t.Errorf("%v: expected unpacked fields { %v } got { %v }", desc, tc.output, fields)
+		}
+	}
+
+	err := eris.WithFields(globalErr, map[string]interface{}{"user": "alice"})
+	if !eris.Is(err, globalErr) || err.Error() != globalErr.Error() {
+		t.Errorf("expected { %v } to still match the global error", err)
+	}
+	if fields := eris.Fields(globalErr); fields != nil {
+		t.Errorf("expected global error to be unchanged but got fields { %v }", fields)
 	}
 }
 
@@ -236,3 +668,36 @@ func TestErrorFormatting(t *testing.T) {
 		_ = fmt.Sprintf("%+v", err)
 	}
 }
+
+func TestErrorFormattingConcurrency(t *testing.T) {
+	err := setupTestCase(false, eris.New("root error"), []string{"additional context", "even more context"})
+	expected := fmt.Sprintf("%+v", err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := fmt.Sprintf("%+v", err); got != expected {
+				t.Errorf("expected { %v } got { %v }", expected, got)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkErrorString(b *testing.B) {
+	err := setupTestCase(false, eris.New("root error"), []string{"additional context", "even more context"})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = err.Error()
+	}
+}
+
+func BenchmarkErrorTrace(b *testing.B) {
+	err := setupTestCase(false, eris.New("root error"), []string{"additional context", "even more context"})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fmt.Sprintf("%+v", err)
+	}
+}
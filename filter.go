@@ -0,0 +1,79 @@
+package eris
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// FrameFilter reports whether a stack frame should be hidden from error output.
+type FrameFilter func(frame StackFrame) bool
+
+// HidePackages returns a FrameFilter that hides the frames of functions in the given packages and their
+// subpackages. Packages are identified by their import path (e.g. "net/http").
+func HidePackages(pkgs ...string) FrameFilter {
+	return func(frame StackFrame) bool {
+		pkg := frame.Package
+		for _, p := range pkgs {
+			if pkg == p || strings.HasPrefix(pkg, p+"/") {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HideVendored is a FrameFilter that hides the frames of vendored packages. It checks the file path recorded at build
+// time, since rewritten paths (e.g. by TrimModulePaths) no longer contain the vendor directory.
+func HideVendored(frame StackFrame) bool {
+	return strings.Contains(frame.buildPath(), "/vendor/")
+}
+
+// DefaultFrameFilters returns the filters applied to stack traces unless they're replaced via SetFrameFilters.
+// They hide the frames of the Go runtime, the testing package, the net/http package, and vendored packages.
+func DefaultFrameFilters() []FrameFilter {
+	return []FrameFilter{
+		HidePackages("runtime", "testing", "net/http"),
+		HideVendored,
+	}
+}
+
+var frameFilters atomic.Value // []FrameFilter
+
+func init() {
+	frameFilters.Store(DefaultFrameFilters())
+}
+
+// SetFrameFilters replaces the filters applied to every formatted stack trace. Calling it without any filters shows
+// every frame. Additional filters can be passed via FrameOptions.Filters, and FrameOptions.ShowAllFrames disables
+// filtering for a single format.
+func SetFrameFilters(filters ...FrameFilter) {
+	frameFilters.Store(append([]FrameFilter(nil), filters...))
+}
+
+// filterFrames returns the frames that aren't hidden by the global filters or the filters of the given options.
+func filterFrames(frames []StackFrame, opts FrameOptions) []StackFrame {
+	if opts.ShowAllFrames {
+		return frames
+	}
+	filters := frameFilters.Load().([]FrameFilter)
+	filters = append(filters[:len(filters):len(filters)], opts.Filters...)
+	if len(filters) == 0 {
+		return frames
+	}
+	var filtered []StackFrame
+	for _, frame := range frames {
+		if !hidden(frame, filters) {
+			filtered = append(filtered, frame)
+		}
+	}
+	return filtered
+}
+
+func hidden(frame StackFrame, filters []FrameFilter) bool {
+	for _, filter := range filters {
+		if filter(frame) {
+			return true
+		}
+	}
+	return false
+}
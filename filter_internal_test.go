@@ -0,0 +1,33 @@
+package eris
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestHideVendoredRewrittenPaths(t *testing.T) {
+	// the build info of a program vendoring example.com/db, whose paths are rewritten by TrimModulePaths
+	loadBuildInfo()
+	defer func(info *debug.BuildInfo) { buildInfo = info }(buildInfo)
+	buildInfo = &debug.BuildInfo{
+		Path: "example.com/app",
+		Main: debug.Module{Path: "example.com/app"},
+		Deps: []*debug.Module{{Path: "example.com/db", Version: "v1.2.3"}},
+	}
+
+	frames := []StackFrame{
+		{Name: "db.Get", File: "/src/app/vendor/example.com/db/db.go", Line: 99, Package: "example.com/db"},
+		{Name: "api.GetResource", File: "/src/app/api/api.go", Line: 30, Package: "example.com/app/api"},
+	}
+	for i := range frames {
+		rewritePath(&frames[i])
+	}
+	if frames[0].File != "example.com/db@v1.2.3/db.go" || frames[1].File != "api/api.go" {
+		t.Fatalf("expected rewritten paths got { %v }", frames)
+	}
+
+	filtered := filterFrames(frames, FrameOptions{})
+	if len(filtered) != 1 || filtered[0].Name != "api.GetResource" {
+		t.Errorf("expected the vendored frame to be hidden got { %v }", filtered)
+	}
+}
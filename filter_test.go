@@ -0,0 +1,94 @@
+package eris_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func TestFrameFilters(t *testing.T) {
+	err := setupTestCase(false, eris.New("root error"), []string{"additional context"})
+
+	tests := map[string]struct {
+		filters []eris.FrameFilter // global filters (defaults if nil)
+		format  eris.Format        // format used for output
+		shown   []string           // frames expected in the output
+		hidden  []string           // frames not expected in the output
+	}{
+		"default filters": {
+			format: eris.NewDefaultFormat(true),
+			shown:  []string{"eris_test.setupTestCase", "eris_test.TestFrameFilters"},
+			hidden: []string{"testing.tRunner", "runtime.goexit"},
+		},
+		"no global filters": {
+			filters: []eris.FrameFilter{},
+			format:  eris.NewDefaultFormat(true),
+			shown:   []string{"eris_test.setupTestCase", "testing.tRunner", "runtime.goexit"},
+		},
+		"format filters": {
+			format: eris.Format{
+				WithTrace: true,
+				Sep:       "\n",
+				FrameOptions: eris.FrameOptions{
+					Filters: []eris.FrameFilter{eris.HidePackages("github.com/rotisserie/eris_test")},
+				},
+			},
+			hidden: []string{"eris_test.TestFrameFilters", "testing.tRunner", "runtime.goexit"},
+		},
+		"show all frames": {
+			format: eris.Format{
+				WithTrace: true,
+				Sep:       "\n",
+				FrameOptions: eris.FrameOptions{
+					Filters:       []eris.FrameFilter{eris.HidePackages("github.com/rotisserie/eris_test")},
+					ShowAllFrames: true,
+				},
+			},
+			shown: []string{"eris_test.TestFrameFilters", "testing.tRunner", "runtime.goexit"},
+		},
+	}
+
+	for desc, tc := range tests {
+		if tc.filters != nil {
+			eris.SetFrameFilters(tc.filters...)
+		}
+		uErr := eris.Unpack(err)
+		str := uErr.ToString(tc.format)
+		for _, name := range tc.shown {
+			if !strings.Contains(str, name) {
+				t.Errorf("%v: expected frame { %v } in output { %v }", desc, name, str)
+			}
+		}
+		for _, name := range tc.hidden {
+			if strings.Contains(str, name) {
+				t.Errorf("%v: expected frame { %v } to be hidden from output { %v }", desc, name, str)
+			}
+		}
+		eris.SetFrameFilters(eris.DefaultFrameFilters()...)
+	}
+}
+
+func TestHideVendored(t *testing.T) {
+	uErr := eris.UnpackedError{
+		ErrRoot: &eris.ErrRoot{
+			Msg: "root error",
+			Stack: []eris.StackFrame{
+				{
+					Name: "db.Get",
+					File: "/path/to/project/vendor/example.com/db/db.go",
+					Line: 99,
+				},
+				{
+					Name: "api.GetResource",
+					File: "/path/to/project/api/api.go",
+					Line: 30,
+				},
+			},
+		},
+	}
+	expected := "root error\n\tapi.GetResource: /path/to/project/api/api.go: 30\n"
+	if got := uErr.ToString(eris.NewDefaultFormat(true)); got != expected {
+		t.Errorf("ToString() = %v, want %v", got, expected)
+	}
+}
@@ -2,6 +2,7 @@ package eris
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Format defines an error output format to be used with the default formatter.
@@ -11,6 +12,25 @@ type Format struct {
 	TBeg      string // Separator at the beginning of each stack frame.
 	TSep      string // Separator between elements of each stack frame.
 	Sep       string // Separator between each error in the chain.
+	BSep      string // Separator between each branch of a multi-error.
+
+	FrameOptions
+}
+
+// FrameOptions controls which stack frames are shown and how they're named. It's shared by the formats of this
+// package.
+type FrameOptions struct {
+	Filters       []FrameFilter // Filters hiding stack frames in addition to the ones set via SetFrameFilters.
+	ShowAllFrames bool          // Flag that disables all stack frame filters.
+	FullNames     bool          // Flag that enables function names with the full package path.
+}
+
+// frameName returns the name of the frame, with the full package path if FullNames is set.
+func (opts FrameOptions) frameName(sFrame StackFrame) string {
+	if opts.FullNames {
+		return sFrame.FullName()
+	}
+	return sFrame.Name
 }
 
 // NewDefaultFormat conveniently returns a basic format for the default string formatter.
@@ -18,12 +38,14 @@ func NewDefaultFormat(withTrace bool) Format {
 	stringFmt := Format{
 		WithTrace: withTrace,
 		Sep:       ": ",
+		BSep:      "; ",
 	}
 	if withTrace {
 		stringFmt.Msg = "\n"
 		stringFmt.TBeg = "\t"
 		stringFmt.TSep = ": "
 		stringFmt.Sep = "\n"
+		stringFmt.BSep = ""
 	}
 	return stringFmt
 }
@@ -32,26 +54,77 @@ func NewDefaultFormat(withTrace bool) Format {
 //
 // This type can be used for custom error logging and parsing. Use `eris.Unpack` to build an UnpackedError
 // from any error type. The ErrChain and ErrRoot fields correspond to `wrapError` and `rootError` types,
-// respectively. If any other error type is unpacked, it will appear in the ExternalErr field.
+// respectively. External errors found in the middle of the chain (e.g. created via fmt.Errorf with the %w verb) are
+// included in ErrChain with their Go type name, and the errors beneath them are unpacked as usual. If any other error
+// type ends the chain, it will appear in the ExternalErr and ExternalType fields. Multi-errors created via eris.Join
+// or eris.Append end the chain with one unpacked error per branch in the ErrBranches field. So do external
+// multi-errors (i.e. errors implementing `Unwrap() []error`, such as the ones created via errors.Join) with eris
+// errors beneath them, which are also included in ErrChain like other external errors. Root errors created via
+// eris.Errorf from other errors have both ErrRoot and ErrBranches, with one branch per %w operand, and since the root
+// error's message already contains the messages of its branches, they're only shown in output with stack traces. The
+// Fields field contains the structured fields of the whole chain, merged as described in eris.Fields, and the Code
+// field contains the outermost code attached to the chain (OK if there isn't one).
 type UnpackedError struct {
-	ErrChain    *[]ErrLink
-	ErrRoot     *ErrRoot
-	ExternalErr string
+	ErrChain     *[]ErrLink
+	ErrRoot      *ErrRoot
+	ExternalErr  string
+	ExternalType string
+	ErrBranches  []UnpackedError
+	Fields       map[string]interface{}
+	Code         Code
 }
 
 // Unpack returns UnpackedError type for a given golang error type.
 func Unpack(err error) UnpackedError {
-	e := UnpackedError{}
-	switch err.(type) {
-	case nil:
+	return unpack(err, true)
+}
+
+// unpack builds an UnpackedError for err. Stack traces are only symbolized if withTrace is set, which keeps
+// formatting errors without their traces cheap.
+func unpack(err error, withTrace bool) UnpackedError {
+	if err == nil {
 		return UnpackedError{}
-	case *rootError:
-		e = unpackRootErr(err.(*rootError))
-	case *wrapError:
-		chain := []ErrLink{}
-		e = unpackWrapErr(&chain, err.(*wrapError))
-	default:
-		e.ExternalErr = err.Error()
+	}
+	e := UnpackedError{
+		Fields: Fields(err),
+		Code:   codeOf(err),
+	}
+	var chain []ErrLink
+	for err != nil {
+		switch x := err.(type) {
+		case *rootError:
+			e.ErrRoot = unpackRootErr(x, withTrace)
+			if j, ok := x.ext.(*joinError); ok {
+				e.ErrBranches = unpackBranches(j.errs, withTrace)
+			}
+			err = nil
+		case *wrapError:
+			chain = append(chain, unpackWrapErr(x, withTrace))
+			err = x.err
+		case *joinError:
+			e.ErrBranches = unpackBranches(x.errs, withTrace)
+			err = nil
+		default:
+			if multi, ok := x.(interface{ Unwrap() []error }); ok && hasErisError(x) {
+				branches := multi.Unwrap()
+				chain = append(chain, unpackExternalErr(x, joinMessages(branches)))
+				e.ErrBranches = unpackBranches(branches, withTrace)
+				err = nil
+				break
+			}
+			next := unwrapCause(x)
+			if !hasErisError(next) {
+				e.ExternalErr = x.Error()
+				e.ExternalType = typeName(x)
+				err = nil
+			} else {
+				chain = append(chain, unpackExternalErr(x, next.Error()))
+				err = next
+			}
+		}
+	}
+	if chain != nil {
+		e.ErrChain = &chain
 	}
 	return e
 }
@@ -65,18 +138,31 @@ func (upErr *UnpackedError) ToString(format Format) string {
 		}
 	}
 	str += upErr.ErrRoot.formatStr(format)
+	for i, branch := range upErr.branches(format.WithTrace) {
+		if i > 0 {
+			str += format.BSep
+		}
+		str += indent(branch.ToString(format), format.TBeg)
+	}
 	if upErr.ExternalErr != "" {
 		str += fmt.Sprint(upErr.ExternalErr)
 	}
 	return str
 }
 
-// ToJSON returns a JSON formatted map for a given eris error.
+// ToJSON returns a JSON formatted map for a given eris error. See ToJSONError for a typed alternative with a stable
+// structure.
 func (upErr *UnpackedError) ToJSON(format Format) map[string]interface{} {
 	if upErr == nil {
 		return nil
 	}
 	jsonMap := make(map[string]interface{})
+	if len(upErr.Fields) > 0 {
+		jsonMap["fields"] = upErr.Fields
+	}
+	if upErr.Code != OK {
+		jsonMap["code"] = upErr.Code.String()
+	}
 	if fmtRootErr := upErr.ErrRoot.formatJSON(format); fmtRootErr != nil {
 		jsonMap["error root"] = fmtRootErr
 	}
@@ -88,49 +174,128 @@ func (upErr *UnpackedError) ToJSON(format Format) map[string]interface{} {
 		}
 		jsonMap["error chain"] = wrapArr
 	}
+	if upErr.ErrBranches != nil {
+		var branchArr []map[string]interface{}
+		for _, branch := range upErr.ErrBranches {
+			branchArr = append(branchArr, branch.ToJSON(format))
+		}
+		jsonMap["error branches"] = branchArr
+	}
 	if upErr.ExternalErr != "" {
 		jsonMap["external error"] = fmt.Sprint(upErr.ExternalErr)
+		if upErr.ExternalType != "" {
+			jsonMap["external type"] = upErr.ExternalType
+		}
 	}
 	return jsonMap
 }
 
-func unpackRootErr(err *rootError) UnpackedError {
-	return UnpackedError{
-		ErrRoot: &ErrRoot{
-			Msg:   err.msg,
-			Stack: err.stack.get(),
-		},
+func unpackRootErr(err *rootError, withTrace bool) *ErrRoot {
+	root := &ErrRoot{
+		Msg:    err.msg,
+		Fields: err.fields,
+		Code:   err.code,
+	}
+	if withTrace {
+		root.Stack = err.stack.get()
+		root.Truncated = err.stack.truncated
 	}
+	return root
 }
 
-func unpackWrapErr(chain *[]ErrLink, err *wrapError) UnpackedError {
+func unpackWrapErr(err *wrapError, withTrace bool) ErrLink {
 	link := ErrLink{}
-	link.Frame = *err.frame.get()
+	if withTrace {
+		link.Frame = *err.frame.get()
+	}
 	link.Msg = err.msg
-	*chain = append(*chain, link)
-
-	e := UnpackedError{}
-	e.ErrChain = chain
-
-	nextErr := err.Unwrap()
-	switch nextErr.(type) {
-	case nil:
-		return e
-	case *rootError:
-		uErr := unpackRootErr(nextErr.(*rootError))
-		e.ErrRoot = uErr.ErrRoot
-	case *wrapError:
-		e = unpackWrapErr(chain, nextErr.(*wrapError))
-	default:
-		e.ExternalErr = err.Error()
+	link.Fields = err.fields
+	link.Code = err.code
+	return link
+}
+
+// unpackExternalErr returns the link for an external error that wraps errors with the message nextMsg. The message of
+// the wrapped errors is removed from the end of the external error's message, so each message is only shown once.
+func unpackExternalErr(err error, nextMsg string) ErrLink {
+	msg := err.Error()
+	if msg == nextMsg {
+		msg = ""
+	} else if strings.HasSuffix(msg, ": "+nextMsg) {
+		msg = msg[:len(msg)-len(nextMsg)-2]
 	}
-	return e
+	return ErrLink{
+		Msg:  msg,
+		Type: typeName(err),
+	}
+}
+
+// branches returns the branches shown in output with or without stack traces. The branches of root errors are only
+// shown with stack traces since the root error's message already contains their messages.
+func (upErr *UnpackedError) branches(withTrace bool) []UnpackedError {
+	if upErr.ErrRoot != nil && !withTrace {
+		return nil
+	}
+	return upErr.ErrBranches
+}
+
+func unpackBranches(errs []error, withTrace bool) []UnpackedError {
+	var branches []UnpackedError
+	for _, branch := range errs {
+		if branch != nil {
+			branches = append(branches, unpack(branch, withTrace))
+		}
+	}
+	return branches
+}
+
+// joinMessages returns the messages of errs separated by newlines, which is the message of multi-errors created by
+// errors.Join.
+func joinMessages(errs []error) string {
+	var msgs []string
+	for _, err := range errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// hasErisError reports whether err or any error beneath it that can be reached via `Unwrap() error`,
+// `Unwrap() []error`, or `Cause() error` is an eris error.
+func hasErisError(err error) bool {
+	for ; err != nil; err = unwrapCause(err) {
+		switch e := err.(type) {
+		case *rootError, *wrapError, *joinError:
+			return true
+		case interface{ Unwrap() []error }:
+			for _, branch := range e.Unwrap() {
+				if hasErisError(branch) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// typeName returns the Go type name of an external error (e.g. "*fs.PathError"). Errors decoded from JSON keep the
+// type name of the original error.
+func typeName(err error) string {
+	if e, ok := err.(*externalError); ok {
+		return e.typ
+	}
+	return fmt.Sprintf("%T", err)
 }
 
-// ErrRoot represents an error stack and the accompanying message.
+// ErrRoot represents an error stack and the accompanying message. Truncated is the number of frames left out of
+// the stack because the maximum stack depth was reached.
 type ErrRoot struct {
-	Msg   string
-	Stack []StackFrame
+	Msg       string                 `json:"message"`
+	Stack     []StackFrame           `json:"stack,omitempty"`
+	Truncated int                    `json:"truncated,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Code      Code                   `json:"code,omitempty"`
 }
 
 func (err *ErrRoot) formatStr(format Format) string {
@@ -140,7 +305,10 @@ func (err *ErrRoot) formatStr(format Format) string {
 	str := err.Msg
 	str += format.Msg
 	if format.WithTrace {
-		stackArr := formatStackFrames(err.Stack, format.TSep)
+		stackArr := formatStackFrames(filterFrames(err.Stack, format.FrameOptions), format)
+		if err.Truncated > 0 {
+			stackArr = append(stackArr, formatTruncated(err.Truncated))
+		}
 		for _, frame := range stackArr {
 			str += format.TBeg
 			str += frame
@@ -157,24 +325,38 @@ func (err *ErrRoot) formatJSON(format Format) map[string]interface{} {
 	rootMap := make(map[string]interface{})
 	rootMap["message"] = fmt.Sprint(err.Msg)
 	if format.WithTrace {
-		rootMap["stack"] = formatStackFrames(err.Stack, format.TSep)
+		stackArr := formatStackFrames(filterFrames(err.Stack, format.FrameOptions), format)
+		if err.Truncated > 0 {
+			stackArr = append(stackArr, formatTruncated(err.Truncated))
+		}
+		rootMap["stack"] = stackArr
 	}
 	return rootMap
 }
 
-// ErrLink represents a single error frame and the accompanying message.
+// ErrLink represents a single error frame and the accompanying message. Type is the Go type name of external errors
+// in the chain, which don't have a stack frame, and it's empty for errors created by eris.
 type ErrLink struct {
-	Msg   string
-	Frame StackFrame
+	Msg    string                 `json:"message"`
+	Frame  StackFrame             `json:"frame"`
+	Type   string                 `json:"type,omitempty"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Code   Code                   `json:"code,omitempty"`
 }
 
 func (eLink *ErrLink) formatStr(format Format) string {
+	if eLink.Type != "" {
+		if eLink.Msg == "" {
+			return ""
+		}
+		return eLink.Msg + format.Sep
+	}
 	var str string
 	str += eLink.Msg
 	str += format.Msg
 	if format.WithTrace {
 		str += format.TBeg
-		str += eLink.Frame.formatFrame(format.TSep)
+		str += eLink.Frame.formatFrame(format)
 	}
 	str += format.Sep
 	return str
@@ -183,16 +365,40 @@ func (eLink *ErrLink) formatStr(format Format) string {
 func (eLink *ErrLink) formatJSON(format Format) map[string]interface{} {
 	wrapMap := make(map[string]interface{})
 	wrapMap["message"] = fmt.Sprint(eLink.Msg)
-	if format.WithTrace {
-		wrapMap["stack"] = eLink.Frame.formatFrame(format.TSep)
+	if eLink.Type != "" {
+		wrapMap["type"] = eLink.Type
+	} else if format.WithTrace {
+		wrapMap["stack"] = eLink.Frame.formatFrame(format)
 	}
 	return wrapMap
 }
 
-func formatStackFrames(s []StackFrame, sep string) []string {
+// formatTruncated returns the marker printed in place of frames left out of a stack trace.
+func formatTruncated(n int) string {
+	if n == 1 {
+		return "... 1 more frame"
+	}
+	return fmt.Sprintf("... %v more frames", n)
+}
+
+// indent prefixes each line of str with the given string.
+func indent(str string, prefix string) string {
+	if prefix == "" {
+		return str
+	}
+	lines := strings.SplitAfter(str, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+func formatStackFrames(s []StackFrame, format Format) []string {
 	var str []string
 	for _, f := range s {
-		str = append(str, f.formatFrame(sep))
+		str = append(str, f.formatFrame(format))
 	}
 	return str
 }
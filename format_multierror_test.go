@@ -0,0 +1,103 @@
+//go:build go1.20
+// +build go1.20
+
+package eris_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func TestUnpackExternalMultiError(t *testing.T) {
+	first := eris.New("first error")
+	second := eris.WithCode(eris.New("second error"), eris.NotFound)
+
+	tests := map[string]struct {
+		input    error          // input error
+		chain    []eris.ErrLink // expected chain (messages and types only)
+		branches []string       // expected output of each branch without trace
+		output   string         // expected output without trace
+		code     eris.Code      // expected code
+	}{
+		"errors.Join": {
+			input:    errors.Join(first, second),
+			chain:    []eris.ErrLink{{Type: "*errors.joinError"}},
+			branches: []string{"first error", "second error"},
+			output:   "first error; second error",
+			code:     eris.NotFound,
+		},
+		"wrapped errors.Join with external branch": {
+			input: eris.Wrap(errors.Join(first, io.EOF), "additional context"),
+			chain: []eris.ErrLink{
+				{Msg: "additional context"},
+				{Type: "*errors.joinError"},
+			},
+			branches: []string{"first error", "EOF"},
+			output:   "additional context: first error; EOF",
+			code:     eris.Unknown,
+		},
+		"fmt.Errorf with several %w": {
+			input: eris.Wrap(fmt.Errorf("two errors (%w, %w)", first, second), "additional context"),
+			chain: []eris.ErrLink{
+				{Msg: "additional context"},
+				{Msg: "two errors (first error, second error)", Type: "*fmt.wrapErrors"},
+			},
+			branches: []string{"first error", "second error"},
+			output:   "additional context: two errors (first error, second error): first error; second error",
+			code:     eris.NotFound,
+		},
+	}
+
+	for desc, tc := range tests {
+		uErr := eris.Unpack(tc.input)
+		if uErr.ErrChain == nil || !errChainsEqual(*uErr.ErrChain, tc.chain) {
+			t.Errorf("%v: expected chain { %v } got { %v }", desc, tc.chain, uErr.ErrChain)
+		}
+		if uErr.ErrRoot != nil || uErr.ExternalErr != "" {
+			t.Errorf("%v: expected only branches to end the chain but got { %v } { %v }", desc, uErr.ErrRoot, uErr.ExternalErr)
+		}
+		if len(uErr.ErrBranches) != len(tc.branches) {
+			t.Fatalf("%v: expected %v branches got { %v }", desc, len(tc.branches), uErr.ErrBranches)
+		}
+		for i, branch := range uErr.ErrBranches {
+			if str := branch.ToString(eris.NewDefaultFormat(false)); str != tc.branches[i] {
+				t.Errorf("%v: expected branch { %v } got { %v }", desc, tc.branches[i], str)
+			}
+		}
+		if str := uErr.ToString(eris.NewDefaultFormat(false)); str != tc.output {
+			t.Errorf("%v: expected { %v } got { %v }", desc, tc.output, str)
+		}
+		if code := eris.CodeOf(tc.input); code != tc.code {
+			t.Errorf("%v: expected code { %v } got { %v }", desc, tc.code, code)
+		}
+
+		// the stack traces of the eris errors beneath are kept rather than replaced with the stack of the wrap call
+		if stack := eris.StackTrace(tc.input); len(stack) == 0 || stack[0].Line != eris.StackTrace(first)[0].Line {
+			t.Errorf("%v: expected the stack trace of the first branch got { %v }", desc, stack)
+		}
+		if !eris.Is(tc.input, first) {
+			t.Errorf("%v: expected { %v } to be in the chain of { %v }", desc, first, tc.input)
+		}
+
+		data, err := json.Marshal(uErr)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", desc, err)
+		}
+		decoded, err := eris.FromJSON(data, first)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", desc, err)
+		}
+		if !eris.Is(decoded, first) {
+			t.Errorf("%v: expected { %v } to be in the chain of the decoded error { %v }", desc, first, decoded)
+		}
+		decodedErr := eris.Unpack(decoded)
+		if str := decodedErr.ToString(eris.NewDefaultFormat(false)); str != tc.output {
+			t.Errorf("%v: expected decoded error { %v } got { %v }", desc, tc.output, str)
+		}
+	}
+}
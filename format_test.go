@@ -3,6 +3,7 @@ package eris_test
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -20,7 +21,7 @@ func errChainsEqual(a []eris.ErrLink, b []eris.ErrLink) bool {
 	}
 
 	for i := range a {
-		if a[i].Msg != b[i].Msg {
+		if a[i].Msg != b[i].Msg || a[i].Type != b[i].Type {
 			return false
 		}
 	}
@@ -89,7 +90,49 @@ func TestUnpack(t *testing.T) {
 		"no error wrapping with external root cause (errors.New)": {
 			cause: errors.New("external error"),
 			output: eris.UnpackedError{
-				ExternalErr: "external error",
+				ExternalErr:  "external error",
+				ExternalType: "*errors.errorString",
+			},
+		},
+		"error wrapping with external error in the chain (fmt.Errorf)": {
+			cause: fmt.Errorf("external context: %w", eris.New("root error")),
+			input: []string{"additional context"},
+			output: eris.UnpackedError{
+				ErrRoot: &eris.ErrRoot{
+					Msg: "root error",
+				},
+				ErrChain: &[]eris.ErrLink{
+					{
+						Msg: "additional context",
+					},
+					{
+						Msg:  "external context",
+						Type: "*fmt.wrapError",
+					},
+				},
+			},
+		},
+		"no error wrapping with external error in the chain (fmt.Errorf)": {
+			cause: fmt.Errorf("%w", eris.Wrap(eris.New("root error"), "additional context")),
+			output: eris.UnpackedError{
+				ErrRoot: &eris.ErrRoot{
+					Msg: "root error",
+				},
+				ErrChain: &[]eris.ErrLink{
+					{
+						Type: "*fmt.wrapError",
+					},
+					{
+						Msg: "additional context",
+					},
+				},
+			},
+		},
+		"external errors in the chain without eris errors beneath them": {
+			cause: fmt.Errorf("external context: %w", errors.New("external error")),
+			output: eris.UnpackedError{
+				ExternalErr:  "external context: external error",
+				ExternalType: "*fmt.wrapError",
 			},
 		},
 	}
@@ -102,6 +145,10 @@ func TestUnpack(t *testing.T) {
 			if got := eris.Unpack(err); got.ErrRoot != nil && tt.output.ErrRoot != nil && !reflect.DeepEqual(got.ErrRoot.Msg, tt.output.ErrRoot.Msg) {
 				t.Errorf("Unpack() ErrorRoot = %v, want %v", got.ErrRoot.Msg, tt.output.ErrRoot.Msg)
 			}
+			if got := eris.Unpack(err); got.ExternalErr != tt.output.ExternalErr || got.ExternalType != tt.output.ExternalType {
+				t.Errorf("Unpack() ExternalErr = %v (%v), want %v (%v)", got.ExternalErr, got.ExternalType,
+					tt.output.ExternalErr, tt.output.ExternalType)
+			}
 		})
 	}
 }
@@ -143,6 +190,53 @@ func TestFormatStr(t *testing.T) {
 			},
 			formattedOutput: "root error\n\teris.TestFormatStr: format_test.go: 99\n\tgolang.Runtime: runtime.go: 100\n",
 		},
+		"wrapped error with external error in the chain": {
+			basicInput: eris.UnpackedError{
+				ErrRoot: &eris.ErrRoot{
+					Msg: "root error",
+				},
+				ErrChain: &[]eris.ErrLink{
+					{
+						Msg: "additional context",
+					},
+					{
+						Msg:  "external context",
+						Type: "*fmt.wrapError",
+					},
+					{
+						Type: "*fmt.wrapError",
+					},
+				},
+			},
+			formattedInput: eris.UnpackedError{
+				ErrRoot: &eris.ErrRoot{
+					Msg: "root error",
+					Stack: []eris.StackFrame{
+						{
+							Name: "eris.TestFormatStr",
+							File: "format_test.go",
+							Line: 99,
+						},
+					},
+				},
+				ErrChain: &[]eris.ErrLink{
+					{
+						Msg: "additional context",
+						Frame: eris.StackFrame{
+							Name: "eris.TestFormatStr",
+							File: "format_test.go",
+							Line: 100,
+						},
+					},
+					{
+						Msg:  "external context",
+						Type: "*fmt.wrapError",
+					},
+				},
+			},
+			basicOutput:     "additional context: external context: root error",
+			formattedOutput: "additional context\n\teris.TestFormatStr: format_test.go: 100\nexternal context\nroot error\n\teris.TestFormatStr: format_test.go: 99\n",
+		},
 		"basic wrapped error": {
 			basicInput: eris.UnpackedError{
 				ErrRoot: &eris.ErrRoot{
@@ -160,6 +254,23 @@ func TestFormatStr(t *testing.T) {
 			formattedInput: eris.UnpackedError{},
 			basicOutput:    "even more context: additional context: root error",
 		},
+		"truncated root error (formatted)": {
+			basicInput: eris.UnpackedError{},
+			formattedInput: eris.UnpackedError{
+				ErrRoot: &eris.ErrRoot{
+					Msg: "root error",
+					Stack: []eris.StackFrame{
+						{
+							Name: "eris.TestFormatStr",
+							File: "format_test.go",
+							Line: 99,
+						},
+					},
+					Truncated: 42,
+				},
+			},
+			formattedOutput: "root error\n\teris.TestFormatStr: format_test.go: 99\n\t... 42 more frames\n",
+		},
 		"basic wrapped error (formatted)": {
 			basicInput: eris.UnpackedError{},
 			formattedInput: eris.UnpackedError{
@@ -198,6 +309,79 @@ func TestFormatStr(t *testing.T) {
 			formattedInput: eris.UnpackedError{},
 			basicOutput:    "external error",
 		},
+		"basic multi-error": {
+			basicInput: eris.UnpackedError{
+				ErrChain: &[]eris.ErrLink{
+					{
+						Msg: "additional context",
+					},
+				},
+				ErrBranches: []eris.UnpackedError{
+					{
+						ErrRoot: &eris.ErrRoot{
+							Msg: "root error",
+						},
+					},
+					{
+						ExternalErr: "external error",
+					},
+				},
+			},
+			formattedInput: eris.UnpackedError{},
+			basicOutput:    "additional context: root error; external error",
+		},
+		"basic multi-error (formatted)": {
+			basicInput: eris.UnpackedError{},
+			formattedInput: eris.UnpackedError{
+				ErrChain: &[]eris.ErrLink{
+					{
+						Msg: "additional context",
+						Frame: eris.StackFrame{
+							Name: "eris.TestFormatStr",
+							File: "format_test.go",
+							Line: 300,
+						},
+					},
+				},
+				ErrBranches: []eris.UnpackedError{
+					{
+						ErrRoot: &eris.ErrRoot{
+							Msg: "root error",
+							Stack: []eris.StackFrame{
+								{
+									Name: "eris.TestFormatStr",
+									File: "format_test.go",
+									Line: 99,
+								},
+							},
+						},
+					},
+					{
+						ErrChain: &[]eris.ErrLink{
+							{
+								Msg: "even more context",
+								Frame: eris.StackFrame{
+									Name: "eris.TestFormatStr",
+									File: "format_test.go",
+									Line: 200,
+								},
+							},
+						},
+						ErrRoot: &eris.ErrRoot{
+							Msg: "another root error",
+							Stack: []eris.StackFrame{
+								{
+									Name: "eris.TestFormatStr",
+									File: "format_test.go",
+									Line: 100,
+								},
+							},
+						},
+					},
+				},
+			},
+			formattedOutput: "additional context\n\teris.TestFormatStr: format_test.go: 300\n\troot error\n\t\teris.TestFormatStr: format_test.go: 99\n\teven more context\n\t\teris.TestFormatStr: format_test.go: 200\n\tanother root error\n\t\teris.TestFormatStr: format_test.go: 100\n",
+		},
 	}
 	for desc, tt := range tests {
 		t.Run(desc, func(t *testing.T) {
@@ -213,6 +397,30 @@ func TestFormatStr(t *testing.T) {
 	}
 }
 
+func TestFormatFullNames(t *testing.T) {
+	uErr := eris.UnpackedError{
+		ErrRoot: &eris.ErrRoot{
+			Msg: "root error",
+			Stack: []eris.StackFrame{
+				{
+					Name:     "api.(*Server).GetResource",
+					File:     "api/api.go",
+					Line:     30,
+					Package:  "example.com/project/api",
+					Receiver: "*Server",
+					Function: "GetResource",
+				},
+			},
+		},
+	}
+	format := eris.NewDefaultFormat(true)
+	format.FullNames = true
+	expected := "root error\n\texample.com/project/api.(*Server).GetResource: api/api.go: 30\n"
+	if got := uErr.ToString(format); got != expected {
+		t.Errorf("ToString() = %v, want %v", got, expected)
+	}
+}
+
 func TestFormatJSON(t *testing.T) {
 	tests := map[string]struct {
 		basicInput      eris.UnpackedError
@@ -310,6 +518,58 @@ func TestFormatJSON(t *testing.T) {
 			basicOutput:     `{"external error":"external error"}`,
 			formattedOutput: `{}`,
 		},
+		"basic error with fields": {
+			basicInput: eris.UnpackedError{
+				ErrRoot: &eris.ErrRoot{
+					Msg:    "root error",
+					Fields: map[string]interface{}{"user": "alice"},
+				},
+				ErrChain: &[]eris.ErrLink{
+					{
+						Msg:    "additional context",
+						Fields: map[string]interface{}{"request": 1},
+					},
+				},
+				Fields: map[string]interface{}{"user": "alice", "request": 1},
+			},
+			formattedInput:  eris.UnpackedError{},
+			basicOutput:     `{"error chain":[{"message":"additional context"}],"error root":{"message":"root error"},"fields":{"request":1,"user":"alice"}}`,
+			formattedOutput: `{}`,
+		},
+		"basic error with code": {
+			basicInput: eris.UnpackedError{
+				ErrRoot: &eris.ErrRoot{
+					Msg:  "root error",
+					Code: eris.NotFound,
+				},
+				Code: eris.NotFound,
+			},
+			formattedInput:  eris.UnpackedError{},
+			basicOutput:     `{"code":"NotFound","error root":{"message":"root error"}}`,
+			formattedOutput: `{}`,
+		},
+		"basic multi-error": {
+			basicInput: eris.UnpackedError{
+				ErrChain: &[]eris.ErrLink{
+					{
+						Msg: "additional context",
+					},
+				},
+				ErrBranches: []eris.UnpackedError{
+					{
+						ErrRoot: &eris.ErrRoot{
+							Msg: "root error",
+						},
+					},
+					{
+						ExternalErr: "external error",
+					},
+				},
+			},
+			formattedInput:  eris.UnpackedError{},
+			basicOutput:     `{"error branches":[{"error root":{"message":"root error"}},{"external error":"external error"}],"error chain":[{"message":"additional context"}]}`,
+			formattedOutput: `{}`,
+		},
 	}
 	for desc, tt := range tests {
 		t.Run(desc, func(t *testing.T) {
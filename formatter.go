@@ -0,0 +1,67 @@
+package eris
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// Formatter writes an unpacked error to w. Formatters control the layout of error output beyond the separators of
+// Format, which is the default Formatter, and they can be registered via SetFormatter to be used when errors are
+// printed with the fmt package.
+type Formatter interface {
+	FormatError(w io.Writer, err UnpackedError) error
+}
+
+// FormatterFunc is an adapter to allow the use of ordinary functions as formatters.
+type FormatterFunc func(w io.Writer, err UnpackedError) error
+
+// FormatError calls f(w, err).
+func (f FormatterFunc) FormatError(w io.Writer, err UnpackedError) error {
+	return f(w, err)
+}
+
+// FormatError implements Formatter by writing the output of ToString.
+func (format Format) FormatError(w io.Writer, err UnpackedError) error {
+	_, writeErr := io.WriteString(w, err.ToString(format))
+	return writeErr
+}
+
+var (
+	formattersMu sync.Mutex
+	formatters   atomic.Value // [2]Formatter, without and with stack traces
+)
+
+func init() {
+	formatters.Store([2]Formatter{NewDefaultFormat(false), NewDefaultFormat(true)})
+}
+
+// SetFormatter replaces the formatter used when eris errors are printed with the %+v verb if withTrace is set, or
+// with any other verb otherwise. Passing a nil formatter restores the default format returned by
+// NewDefaultFormat(withTrace).
+//
+// Stack traces are only unpacked for the %+v verb, so the formatter used for other verbs receives errors without
+// stack frames.
+func SetFormatter(withTrace bool, formatter Formatter) {
+	if formatter == nil {
+		formatter = NewDefaultFormat(withTrace)
+	}
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	fs := formatters.Load().([2]Formatter)
+	if withTrace {
+		fs[1] = formatter
+	} else {
+		fs[0] = formatter
+	}
+	formatters.Store(fs)
+}
+
+// formatter returns the formatter registered via SetFormatter.
+func formatter(withTrace bool) Formatter {
+	fs := formatters.Load().([2]Formatter)
+	if withTrace {
+		return fs[1]
+	}
+	return fs[0]
+}
@@ -0,0 +1,93 @@
+package eris_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+// chainFormatter writes each message of the error chain on its own line, followed by the root error.
+var chainFormatter = eris.FormatterFunc(func(w io.Writer, uErr eris.UnpackedError) error {
+	if uErr.ErrChain != nil {
+		for _, eLink := range *uErr.ErrChain {
+			if _, err := fmt.Fprintf(w, "- %v\n", eLink.Msg); err != nil {
+				return err
+			}
+		}
+	}
+	if uErr.ErrRoot != nil {
+		_, err := fmt.Fprintf(w, "root: %v (%v frames)", uErr.ErrRoot.Msg, len(uErr.ErrRoot.Stack))
+		return err
+	}
+	return nil
+})
+
+func TestFormatter(t *testing.T) {
+	err := eris.Wrap(eris.New("root error"), "additional context")
+
+	tests := map[string]struct {
+		withTrace bool           // whether the formatter is set for %+v
+		formatter eris.Formatter // formatter passed to SetFormatter
+		verb      string         // verb used to print the error
+		output    string         // expected output prefix
+	}{
+		"default format": {
+			verb:   "%v",
+			output: "additional context: root error",
+		},
+		"custom formatter": {
+			formatter: chainFormatter,
+			verb:      "%v",
+			output:    "- additional context\nroot: root error (0 frames)",
+		},
+		"custom formatter with trace": {
+			withTrace: true,
+			formatter: chainFormatter,
+			verb:      "%+v",
+			output:    "- additional context\nroot: root error (",
+		},
+		"custom formatter for %+v only": {
+			withTrace: true,
+			formatter: chainFormatter,
+			verb:      "%v",
+			output:    "additional context: root error",
+		},
+		"custom format": {
+			formatter: eris.Format{Sep: " <- "},
+			verb:      "%s",
+			output:    "additional context <- root error",
+		},
+	}
+
+	for desc, tc := range tests {
+		eris.SetFormatter(tc.withTrace, tc.formatter)
+		output := fmt.Sprintf(tc.verb, err)
+		if !strings.HasPrefix(output, tc.output) || (tc.verb != "%+v" && output != tc.output) {
+			t.Errorf("%v: expected { %v } got { %v }", desc, tc.output, output)
+		}
+		eris.SetFormatter(tc.withTrace, nil)
+	}
+
+	if output, expected := fmt.Sprintf("%v", err), "additional context: root error"; output != expected {
+		t.Errorf("expected the default format to be restored { %v } got { %v }", expected, output)
+	}
+}
+
+func TestFormatFormatError(t *testing.T) {
+	err := eris.Wrap(eris.New("root error"), "additional context")
+	uErr := eris.Unpack(err)
+	for _, withTrace := range []bool{false, true} {
+		format := eris.NewDefaultFormat(withTrace)
+		buf := &bytes.Buffer{}
+		if writeErr := format.FormatError(buf, uErr); writeErr != nil {
+			t.Fatalf("unexpected error: %v", writeErr)
+		}
+		if expected := uErr.ToString(format); buf.String() != expected {
+			t.Errorf("expected { %v } got { %v }", expected, buf.String())
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package eris
+
+import (
+	"context"
+	"sync"
+)
+
+// Group runs functions in separate goroutines and collects the errors they return, similar to
+// golang.org/x/sync/errgroup. The errors are wrapped with the message "goroutine" and the frame of the Go call that
+// started the goroutine, so they show where the goroutine was started in addition to where the error occurred.
+// External errors get a root error with the stack trace of the Go call, and panics are recovered and turned into
+// errors via RecoverPanic.
+//
+// The zero value is a valid Group that doesn't cancel anything on errors.
+//
+//	var g eris.Group
+//	for _, id := range ids {
+//		id := id
+//		g.Go(func() error {
+//			return process(id)
+//		})
+//	}
+//	err := g.WaitAll()
+type Group struct {
+	wg     sync.WaitGroup
+	cancel func()
+
+	mu   sync.Mutex
+	errs []error
+}
+
+// GroupWithContext returns a new Group and a context derived from ctx. The context is canceled when a function passed
+// to Go returns an error or panics for the first time, or when Wait or WaitAll returns, whichever occurs first.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go calls f in a new goroutine. The stack trace of the caller is recorded so errors returned by f can be wrapped with
+// it.
+func (g *Group) Go(f func() error) {
+	spawn := callers(3)
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				g.fail(spawnWrap(recoverPanic(r, 4), spawn))
+			}
+		}()
+		if err := f(); err != nil {
+			g.fail(spawnWrap(err, spawn))
+		}
+	}()
+}
+
+// Wait blocks until all function calls from Go have returned, then returns the first error returned by any of them,
+// or nil if there wasn't any.
+func (g *Group) Wait() error {
+	g.wait()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	return g.errs[0]
+}
+
+// WaitAll blocks until all function calls from Go have returned, then returns a multi-error with one branch per
+// error in the order the errors occurred, or nil if there wasn't any.
+func (g *Group) WaitAll() error {
+	g.wait()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	return &joinError{errs: append([]error(nil), g.errs...)}
+}
+
+func (g *Group) wait() {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+}
+
+func (g *Group) fail(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.errs = append(g.errs, err)
+	if len(g.errs) == 1 && g.cancel != nil {
+		g.cancel()
+	}
+}
+
+// spawnWrap wraps an error returned by a goroutine with the frame of the caller that started it. External errors
+// are turned into root errors with the stack trace of that caller unless they carry their own stack trace.
+func spawnWrap(err error, spawn *stack) error {
+	switch e := err.(type) {
+	case *rootError, *wrapError, *joinError:
+	default:
+		if hasErisError(e) {
+			break
+		}
+		stack := externalStack(e, 0)
+		if stack == nil {
+			stack = spawn
+		}
+		err = &rootError{
+			msg:   e.Error(),
+			ext:   e,
+			stack: stack,
+		}
+	}
+	return &wrapError{
+		msg:   "goroutine",
+		err:   err,
+		frame: spawn.frame(),
+	}
+}
@@ -0,0 +1,124 @@
+package eris_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func TestGroup(t *testing.T) {
+	globalErr := eris.New("global error")
+
+	tests := map[string]struct {
+		funcs  []func() error // functions run in the group
+		output []string       // expected error messages in any order
+		is     []error        // errors expected in the chain of the combined error
+	}{
+		"no errors": {
+			funcs: []func() error{
+				func() error { return nil },
+				func() error { return nil },
+			},
+		},
+		"eris error": {
+			funcs: []func() error{
+				func() error { return nil },
+				func() error { return eris.Wrap(globalErr, "additional context") },
+			},
+			output: []string{"goroutine: additional context: global error"},
+			is:     []error{globalErr},
+		},
+		"external error": {
+			funcs: []func() error{
+				func() error { return io.EOF },
+			},
+			output: []string{"goroutine: EOF"},
+			is:     []error{io.EOF},
+		},
+		"panic": {
+			funcs: []func() error{
+				func() error { panic("something went wrong") },
+			},
+			output: []string{"goroutine: panic: something went wrong"},
+		},
+		"multiple errors": {
+			funcs: []func() error{
+				func() error { return globalErr },
+				func() error { return io.EOF },
+				func() error { return nil },
+			},
+			output: []string{"goroutine: global error", "goroutine: EOF"},
+			is:     []error{globalErr, io.EOF},
+		},
+	}
+
+	for desc, tc := range tests {
+		var g eris.Group
+		for _, f := range tc.funcs {
+			g.Go(f)
+		}
+		err := g.WaitAll()
+		if len(tc.output) == 0 {
+			if err != nil || g.Wait() != nil {
+				t.Errorf("%v: expected no error but got { %v }", desc, err)
+			}
+			continue
+		}
+
+		uErr := eris.Unpack(err)
+		if len(uErr.ErrBranches) != len(tc.output) {
+			t.Fatalf("%v: expected %v errors but got { %v }", desc, len(tc.output), err)
+		}
+		for _, branch := range uErr.ErrBranches {
+			msg := branch.ToString(eris.NewDefaultFormat(false))
+			if !contains(tc.output, msg) {
+				t.Errorf("%v: unexpected error { %v }, expected one of { %v }", desc, msg, tc.output)
+			}
+			if link := (*branch.ErrChain)[0]; link.Frame.Name != "eris_test.TestGroup" {
+				t.Errorf("%v: expected the frame of the Go call but got { %v }", desc, link.Frame.Name)
+			}
+		}
+		for _, target := range tc.is {
+			if !eris.Is(err, target) {
+				t.Errorf("%v: expected { %v } to be in the chain of { %v }", desc, target, err)
+			}
+		}
+		if first := g.Wait(); first == nil || !contains(tc.output, first.Error()) {
+			t.Errorf("%v: expected the first error but got { %v }", desc, first)
+		}
+	}
+}
+
+func TestGroupWithContext(t *testing.T) {
+	g, ctx := eris.GroupWithContext(context.Background())
+	g.Go(func() error {
+		return errors.New("external error")
+	})
+	g.Go(func() error {
+		<-ctx.Done()
+		return nil
+	})
+	if err := g.Wait(); err == nil || err.Error() != "goroutine: external error" {
+		t.Errorf("expected the first error but got { %v }", err)
+	}
+	if ctx.Err() == nil {
+		t.Errorf("expected the context to be canceled")
+	}
+
+	// external errors get the stack trace of the Go call
+	if stack := eris.StackTrace(g.Wait()); len(stack) == 0 || stack[0].Name != "eris_test.TestGroupWithContext" {
+		t.Errorf("expected stack trace starting with the Go call { %v }", stack)
+	}
+}
+
+func contains(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
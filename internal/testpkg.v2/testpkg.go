@@ -0,0 +1,10 @@
+// Package testpkg is used by the tests of eris to create errors in a package whose import path ends with a dotted
+// element, which the runtime escapes in function names.
+package testpkg
+
+import "github.com/rotisserie/eris"
+
+// New returns a new root error created in this package.
+func New() error {
+	return eris.New("dotted package")
+}
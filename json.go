@@ -0,0 +1,411 @@
+package eris
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// jsonError is the JSON representation of an UnpackedError. Unlike the output of ToJSON, it contains every stack
+// frame in structured form, so the error can be decoded again via FromJSON.
+type jsonError struct {
+	Chain        []ErrLink              `json:"chain,omitempty"`
+	Root         *ErrRoot               `json:"root,omitempty"`
+	External     string                 `json:"external,omitempty"`
+	ExternalType string                 `json:"externalType,omitempty"`
+	Branches     []UnpackedError        `json:"branches,omitempty"`
+	Fields       map[string]interface{} `json:"fields,omitempty"`
+	Code         Code                   `json:"code,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The resulting JSON contains the complete error chain including all stack
+// frames and can be decoded via FromJSON or UnpackedError.UnmarshalJSON.
+func (upErr UnpackedError) MarshalJSON() ([]byte, error) {
+	jsonErr := jsonError{
+		Root:         upErr.ErrRoot,
+		External:     upErr.ExternalErr,
+		ExternalType: upErr.ExternalType,
+		Branches:     upErr.ErrBranches,
+		Fields:       upErr.Fields,
+		Code:         upErr.Code,
+	}
+	if upErr.ErrChain != nil {
+		jsonErr.Chain = *upErr.ErrChain
+	}
+	return json.Marshal(jsonErr)
+}
+
+// UnmarshalJSON implements json.Unmarshaler for JSON produced by UnpackedError.MarshalJSON.
+func (upErr *UnpackedError) UnmarshalJSON(data []byte) error {
+	var jsonErr jsonError
+	if err := json.Unmarshal(data, &jsonErr); err != nil {
+		return err
+	}
+	*upErr = UnpackedError{
+		ErrRoot:      jsonErr.Root,
+		ExternalErr:  jsonErr.External,
+		ExternalType: jsonErr.ExternalType,
+		ErrBranches:  jsonErr.Branches,
+		Fields:       jsonErr.Fields,
+		Code:         jsonErr.Code,
+	}
+	if jsonErr.Chain != nil {
+		upErr.ErrChain = &jsonErr.Chain
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler by encoding the unpacked error.
+func (e *rootError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Unpack(e))
+}
+
+// MarshalJSON implements json.Marshaler by encoding the unpacked error.
+func (e *wrapError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Unpack(e))
+}
+
+// MarshalJSON implements json.Marshaler by encoding the unpacked error.
+func (e *joinError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Unpack(e))
+}
+
+// FromJSON decodes an error encoded via json.Marshal, e.g. by another service, and returns it as an eris error. The
+// second return value is non-nil if data isn't a valid encoded error.
+//
+// The decoded error has the same message, stack frames, fields, and codes as the original error, so Error, Unpack,
+// and the formatting functions behave the same for both. Error identity can't be encoded, so root and external errors
+// are matched against the given sentinel errors by message: eris.Is reports true for a decoded error and a sentinel
+// error if the original error matched a sentinel error with the same message. Other external errors are decoded as
+// errors with the original message that report the original type name in UnpackedError. Field values are decoded the
+// same way as by json.Unmarshal, e.g. numbers become float64 values.
+//
+//	data, _ := json.Marshal(eris.Wrap(ErrNotFound, "error getting resource"))
+//	...
+//	err, _ := eris.FromJSON(data, ErrNotFound)
+//	eris.Is(err, ErrNotFound) // true
+func FromJSON(data []byte, sentinels ...error) (error, error) {
+	var upErr UnpackedError
+	if err := json.Unmarshal(data, &upErr); err != nil {
+		return nil, err
+	}
+	// valid JSON such as {} or null doesn't contain any part of an error, and decoding it as a nil error would turn
+	// the failure it was supposed to report into a success
+	if upErr.ErrRoot == nil && upErr.ErrChain == nil && upErr.ErrBranches == nil && upErr.ExternalErr == "" {
+		return nil, errors.New("eris: data doesn't contain an encoded error")
+	}
+	return decode(upErr, sentinels), nil
+}
+
+// decode builds an error from an unpacked error, restoring the identity of the given sentinel errors.
+func decode(upErr UnpackedError, sentinels []error) error {
+	var err error
+	switch {
+	case upErr.ErrRoot != nil:
+		root := decodeRoot(upErr.ErrRoot, sentinels)
+		// root errors created via Errorf unwrap to their branches
+		if upErr.ErrBranches != nil && root.ext == nil {
+			root.ext = &joinError{errs: decodeBranches(upErr.ErrBranches, sentinels)}
+		}
+		err = root
+	case upErr.ErrBranches != nil:
+		// the fields and code of a multi-error are only available merged with the rest of the chain, and since outer
+		// values take precedence, attaching the merged values to the multi-error results in the same values
+		err = &joinError{
+			errs: decodeBranches(upErr.ErrBranches, sentinels),
+			meta: meta{fields: upErr.Fields, code: upErr.Code},
+		}
+	case upErr.ExternalErr != "":
+		err = findSentinel(upErr.ExternalErr, sentinels)
+		if err == nil {
+			err = &externalError{msg: upErr.ExternalErr, typ: upErr.ExternalType}
+		}
+	}
+
+	if upErr.ErrChain != nil {
+		chain := *upErr.ErrChain
+		for i := len(chain) - 1; i >= 0; i-- {
+			if chain[i].Type != "" {
+				err = decodeExternal(chain[i], err)
+				continue
+			}
+			sFrame := chain[i].Frame
+			err = &wrapError{
+				msg:   chain[i].Msg,
+				err:   err,
+				meta:  meta{fields: chain[i].Fields, code: chain[i].Code},
+				frame: &frame{decoded: &sFrame},
+			}
+		}
+	}
+	return err
+}
+
+func decodeBranches(upErrs []UnpackedError, sentinels []error) []error {
+	branches := make([]error, 0, len(upErrs))
+	for _, branch := range upErrs {
+		branches = append(branches, decode(branch, sentinels))
+	}
+	return branches
+}
+
+func decodeRoot(root *ErrRoot, sentinels []error) *rootError {
+	stack := &stack{
+		decoded:   root.Stack,
+		truncated: root.Truncated,
+	}
+	var e *rootError
+	switch sentinel := findSentinel(root.Msg, sentinels).(type) {
+	case *rootError:
+		e = sentinel.copy(stack)
+	case nil:
+		e = &rootError{msg: root.Msg, stack: stack}
+	default:
+		e = &rootError{msg: root.Msg, ext: sentinel, stack: stack}
+	}
+	e.meta = meta{fields: root.Fields, code: root.Code}
+	return e
+}
+
+// decodeExternal returns an external error wrapping err for an external link of a chain. The message of err was
+// removed from the message of the link when it was unpacked, so it's added back.
+func decodeExternal(link ErrLink, err error) error {
+	msg := link.Msg
+	if err != nil {
+		if msg == "" {
+			msg = err.Error()
+		} else {
+			msg += ": " + err.Error()
+		}
+	}
+	return &externalError{msg: msg, typ: link.Type, err: err}
+}
+
+// externalError stands in for an external error decoded from JSON, which can't be restored with its original type.
+type externalError struct {
+	msg string
+	typ string // type name of the original error
+	err error
+}
+
+func (e *externalError) Error() string {
+	return e.msg
+}
+
+func (e *externalError) Unwrap() error {
+	return e.err
+}
+
+// findSentinel returns the first sentinel error with the given message. The messages of root errors exclude the
+// errors they wrap, and other eris errors are never matched.
+func findSentinel(msg string, sentinels []error) error {
+	for _, sentinel := range sentinels {
+		switch e := sentinel.(type) {
+		case nil, *wrapError, *joinError:
+		case *rootError:
+			if e.msg == msg {
+				return e
+			}
+		default:
+			if e.Error() == msg {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// JSONVersion is the version of the JSON structure returned by UnpackedError.ToJSONError and described by JSONSchema.
+// It's incremented whenever the structure changes in a way that isn't backwards compatible.
+const JSONVersion = 1
+
+// JSONError is the typed JSON representation of an error returned by UnpackedError.ToJSONError. Unlike the map
+// returned by ToJSON, it has stable keys without spaces and structured stack frames, and it's described by the JSON
+// Schema document in JSONSchema.
+type JSONError struct {
+	Version      int                    `json:"version,omitempty"` // Set to JSONVersion for the outermost error.
+	Chain        []JSONLink             `json:"chain,omitempty"`
+	Root         *JSONRoot              `json:"root,omitempty"`
+	External     string                 `json:"external,omitempty"`
+	ExternalType string                 `json:"externalType,omitempty"`
+	Branches     []JSONError            `json:"branches,omitempty"`
+	Fields       map[string]interface{} `json:"fields,omitempty"`
+	Code         Code                   `json:"code,omitempty"`
+}
+
+// JSONRoot is the typed JSON representation of a root error.
+type JSONRoot struct {
+	Message   string                 `json:"message"`
+	Stack     []JSONFrame            `json:"stack,omitempty"`
+	Truncated int                    `json:"truncated,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Code      Code                   `json:"code,omitempty"`
+}
+
+// JSONLink is the typed JSON representation of a wrap error or an external error in the middle of a chain. Type is
+// only set for external errors, which don't have a stack frame.
+type JSONLink struct {
+	Message string                 `json:"message"`
+	Frame   *JSONFrame             `json:"frame,omitempty"`
+	Type    string                 `json:"type,omitempty"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+	Code    Code                   `json:"code,omitempty"`
+}
+
+// JSONFrame is the typed JSON representation of a stack frame.
+type JSONFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// ToJSONError returns the typed JSON representation of an unpacked error. Stack frames are only included if the
+// format has WithTrace set, and they're filtered and named according to the format like the output of ToJSON.
+func (upErr *UnpackedError) ToJSONError(format Format) JSONError {
+	jsonErr := upErr.toJSONError(format)
+	jsonErr.Version = JSONVersion
+	return jsonErr
+}
+
+func (upErr *UnpackedError) toJSONError(format Format) JSONError {
+	jsonErr := JSONError{
+		External:     upErr.ExternalErr,
+		ExternalType: upErr.ExternalType,
+		Fields:       upErr.Fields,
+		Code:         upErr.Code,
+	}
+	if upErr.ErrChain != nil {
+		for _, eLink := range *upErr.ErrChain {
+			jsonLink := JSONLink{
+				Message: eLink.Msg,
+				Type:    eLink.Type,
+				Fields:  eLink.Fields,
+				Code:    eLink.Code,
+			}
+			if format.WithTrace && eLink.Type == "" {
+				jsonFrame := newJSONFrame(eLink.Frame, format)
+				jsonLink.Frame = &jsonFrame
+			}
+			jsonErr.Chain = append(jsonErr.Chain, jsonLink)
+		}
+	}
+	if upErr.ErrRoot != nil {
+		jsonErr.Root = &JSONRoot{
+			Message: upErr.ErrRoot.Msg,
+			Fields:  upErr.ErrRoot.Fields,
+			Code:    upErr.ErrRoot.Code,
+		}
+		if format.WithTrace {
+			for _, sFrame := range filterFrames(upErr.ErrRoot.Stack, format.FrameOptions) {
+				jsonErr.Root.Stack = append(jsonErr.Root.Stack, newJSONFrame(sFrame, format))
+			}
+			jsonErr.Root.Truncated = upErr.ErrRoot.Truncated
+		}
+	}
+	for _, branch := range upErr.ErrBranches {
+		jsonErr.Branches = append(jsonErr.Branches, branch.toJSONError(format))
+	}
+	return jsonErr
+}
+
+func newJSONFrame(sFrame StackFrame, format Format) JSONFrame {
+	jsonFrame := JSONFrame{
+		Function: format.frameName(sFrame),
+		File:     sFrame.File,
+		Line:     sFrame.Line,
+	}
+	return jsonFrame
+}
+
+// JSONSchema is a JSON Schema document describing the JSON encoding of JSONError.
+const JSONSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "https://github.com/rotisserie/eris/error.schema.json",
+  "title": "eris error",
+  "type": "object",
+  "required": ["version"],
+  "properties": {
+    "version": {"const": 1},
+    "chain": {"$ref": "#/definitions/chain"},
+    "root": {"$ref": "#/definitions/root"},
+    "external": {"type": "string"},
+    "externalType": {"type": "string"},
+    "branches": {"$ref": "#/definitions/branches"},
+    "fields": {"$ref": "#/definitions/fields"},
+    "code": {"$ref": "#/definitions/code"}
+  },
+  "additionalProperties": false,
+  "definitions": {
+    "error": {
+      "type": "object",
+      "properties": {
+        "chain": {"$ref": "#/definitions/chain"},
+        "root": {"$ref": "#/definitions/root"},
+        "external": {"type": "string"},
+        "externalType": {"type": "string"},
+        "branches": {"$ref": "#/definitions/branches"},
+        "fields": {"$ref": "#/definitions/fields"},
+        "code": {"$ref": "#/definitions/code"}
+      },
+      "additionalProperties": false
+    },
+    "branches": {
+      "type": "array",
+      "items": {"$ref": "#/definitions/error"}
+    },
+    "chain": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "required": ["message"],
+        "properties": {
+          "message": {"type": "string"},
+          "frame": {"$ref": "#/definitions/frame"},
+          "type": {"type": "string"},
+          "fields": {"$ref": "#/definitions/fields"},
+          "code": {"$ref": "#/definitions/code"}
+        },
+        "additionalProperties": false
+      }
+    },
+    "root": {
+      "type": "object",
+      "required": ["message"],
+      "properties": {
+        "message": {"type": "string"},
+        "stack": {
+          "type": "array",
+          "items": {"$ref": "#/definitions/frame"}
+        },
+        "truncated": {"type": "integer", "minimum": 1},
+        "fields": {"$ref": "#/definitions/fields"},
+        "code": {"$ref": "#/definitions/code"}
+      },
+      "additionalProperties": false
+    },
+    "frame": {
+      "type": "object",
+      "required": ["function", "file", "line"],
+      "properties": {
+        "function": {"type": "string"},
+        "file": {"type": "string"},
+        "line": {"type": "integer"}
+      },
+      "additionalProperties": false
+    },
+    "fields": {
+      "type": "object"
+    },
+    "code": {
+      "type": "string",
+      "anyOf": [
+        {
+          "enum": ["Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded", "NotFound", "AlreadyExists",
+            "PermissionDenied", "ResourceExhausted", "FailedPrecondition", "Aborted", "OutOfRange", "Unimplemented",
+            "Internal", "Unavailable", "DataLoss", "Unauthenticated"]
+        },
+        {"pattern": "^Code\\(-?[0-9]+\\)$"}
+      ]
+    }
+  }
+}
+`
@@ -0,0 +1,353 @@
+package eris_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+var errJSONSentinel = eris.New("sentinel error")
+
+func TestErrorJSON(t *testing.T) {
+	tests := map[string]struct {
+		input     error   // input error
+		sentinels []error // sentinel errors passed to FromJSON
+		is        []error // sentinel errors the decoded error is expected to match
+	}{
+		"root error": {
+			input: eris.New("root error"),
+		},
+		"wrapped error": {
+			input: eris.Wrap(eris.Wrap(eris.New("root error"), "additional context"), "even more context"),
+		},
+		"wrapped sentinel error": {
+			input:     eris.Wrap(errJSONSentinel, "additional context"),
+			sentinels: []error{io.EOF, errJSONSentinel},
+			is:        []error{errJSONSentinel},
+		},
+		"wrapped external error": {
+			input:     eris.Wrap(io.EOF, "additional context"),
+			sentinels: []error{errJSONSentinel, io.EOF},
+			is:        []error{io.EOF},
+		},
+		"external error": {
+			input:     io.ErrUnexpectedEOF,
+			sentinels: []error{io.ErrUnexpectedEOF},
+			is:        []error{io.ErrUnexpectedEOF},
+		},
+		"unknown external error": {
+			input: errors.New("external error"),
+		},
+		"external errors in the chain": {
+			input: eris.Wrap(fmt.Errorf("external context: %w", fmt.Errorf("%w", eris.Wrap(
+				fmt.Errorf("more external context: %w", errJSONSentinel), "additional context",
+			))), "even more context"),
+			sentinels: []error{errJSONSentinel},
+			is:        []error{errJSONSentinel},
+		},
+		"multi-error": {
+			input: eris.Wrap(eris.Join(
+				errJSONSentinel,
+				eris.Wrap(io.EOF, "additional context"),
+				eris.WithCode(eris.New("root error"), eris.NotFound),
+			), "even more context"),
+			sentinels: []error{errJSONSentinel, io.EOF},
+			is:        []error{errJSONSentinel, io.EOF},
+		},
+		"fields and codes": {
+			input: eris.WithFields(eris.Wrap(eris.WithCode(eris.WithFields(
+				eris.New("root error"),
+				map[string]interface{}{"user": "alice", "request": "1"},
+			), eris.NotFound), "additional context"), map[string]interface{}{"request": "2"}),
+		},
+		"truncated stack": {
+			input: eris.NewWithDepth("root error", 1),
+		},
+	}
+
+	for desc, tc := range tests {
+		t.Run(desc, func(t *testing.T) {
+			data, err := json.Marshal(eris.Unpack(tc.input))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if _, ok := tc.input.(json.Marshaler); ok {
+				if errData, _ := json.Marshal(tc.input); string(errData) != string(data) {
+					t.Errorf("expected error JSON { %s } got { %s }", data, errData)
+				}
+			}
+			decoded, err := eris.FromJSON(data, tc.sentinels...)
+			if err != nil {
+				t.Fatalf("FromJSON() error = %v", err)
+			}
+			if decoded.Error() != tc.input.Error() {
+				t.Errorf("expected message { %v } got { %v }", tc.input.Error(), decoded.Error())
+			}
+			if expected, got := fmt.Sprintf("%+v", tc.input), fmt.Sprintf("%+v", decoded); got != expected {
+				t.Errorf("expected trace { %v } got { %v }", expected, got)
+			}
+			if decodedData, _ := json.Marshal(eris.Unpack(decoded)); string(decodedData) != string(data) {
+				t.Errorf("expected unpacked error { %s } got { %s }", data, decodedData)
+			}
+			for _, sentinel := range tc.is {
+				if !eris.Is(decoded, sentinel) {
+					t.Errorf("expected decoded error to match { %v }", sentinel)
+				}
+			}
+			if eris.Is(decoded, eris.New("sentinel error")) {
+				t.Errorf("expected decoded error not to match an unrelated error")
+			}
+		})
+	}
+}
+
+func TestFromJSONInvalid(t *testing.T) {
+	tests := map[string]string{
+		"invalid json": `{"root":`,
+		"invalid code": `{"root":{"message":"root error","code":"Nonexistent"}}`,
+		"empty object": `{}`,
+		"null":         `null`,
+		"unknown keys": `{"foo":1}`,
+	}
+	for desc, data := range tests {
+		if _, err := eris.FromJSON([]byte(data)); err == nil {
+			t.Errorf("%v: expected an error decoding { %v }", desc, data)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(eris.JSONSchema), &schema); err != nil {
+		t.Fatalf("invalid JSON schema: %v", err)
+	}
+
+	tests := map[string]struct {
+		input  error       // input error
+		format eris.Format // format used for output
+	}{
+		"root error": {
+			input:  eris.New("root error"),
+			format: eris.NewDefaultFormat(true),
+		},
+		"wrapped error without trace": {
+			input:  eris.Wrap(eris.New("root error"), "additional context"),
+			format: eris.NewDefaultFormat(false),
+		},
+		"external error": {
+			input:  io.EOF,
+			format: eris.NewDefaultFormat(true),
+		},
+		"external error in the chain": {
+			input:  eris.Wrap(fmt.Errorf("external context: %w", eris.New("root error")), "additional context"),
+			format: eris.NewDefaultFormat(true),
+		},
+		"multi-error": {
+			input: eris.Wrap(eris.Join(
+				eris.New("root error"),
+				io.EOF,
+				eris.Join(eris.New("nested error"), io.ErrUnexpectedEOF),
+			), "additional context"),
+			format: eris.NewDefaultFormat(true),
+		},
+		"fields, codes and truncated stack": {
+			input: eris.WithCode(eris.Wrap(eris.WithFields(
+				eris.NewWithDepth("root error", 1),
+				map[string]interface{}{"user": "alice", "attempt": 3},
+			), "additional context"), eris.Code(100)),
+			format: eris.Format{WithTrace: true, FrameOptions: eris.FrameOptions{FullNames: true}},
+		},
+	}
+
+	for desc, tc := range tests {
+		t.Run(desc, func(t *testing.T) {
+			uErr := eris.Unpack(tc.input)
+			jsonErr := uErr.ToJSONError(tc.format)
+			if jsonErr.Version != eris.JSONVersion {
+				t.Errorf("expected version { %v } got { %v }", eris.JSONVersion, jsonErr.Version)
+			}
+			data, err := json.Marshal(jsonErr)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			var doc interface{}
+			_ = json.Unmarshal(data, &doc)
+			if err := validateSchema(schema, schema, doc, "$"); err != nil {
+				t.Errorf("output { %s } doesn't match the schema: %v", data, err)
+			}
+		})
+	}
+}
+
+func TestToJSONError(t *testing.T) {
+	uErr := eris.UnpackedError{
+		ErrChain: &[]eris.ErrLink{
+			{
+				Msg: "additional context",
+				Frame: eris.StackFrame{
+					Name:     "api.(*Server).GetResource",
+					File:     "api/api.go",
+					Line:     30,
+					Package:  "example.com/project/api",
+					Receiver: "*Server",
+					Function: "GetResource",
+				},
+				Code: eris.NotFound,
+			},
+		},
+		ErrRoot: &eris.ErrRoot{
+			Msg: "root error",
+			Stack: []eris.StackFrame{
+				{Name: "db.Get", File: "db/db.go", Line: 99, Package: "example.com/project/db", Function: "Get"},
+				{Name: "testing.tRunner", File: "$GOROOT/src/testing/testing.go", Line: 1, Package: "testing"},
+			},
+			Truncated: 2,
+			Fields:    map[string]interface{}{"user": "alice"},
+		},
+		Fields: map[string]interface{}{"user": "alice"},
+		Code:   eris.NotFound,
+	}
+
+	tests := map[string]struct {
+		format eris.Format // format used for output
+		output string      // expected output
+	}{
+		"with trace": {
+			format: eris.NewDefaultFormat(true),
+			output: `{"version":1,` +
+				`"chain":[{"message":"additional context",` +
+				`"frame":{"function":"api.(*Server).GetResource","file":"api/api.go","line":30},"code":"NotFound"}],` +
+				`"root":{"message":"root error","stack":[{"function":"db.Get","file":"db/db.go","line":99}],` +
+				`"truncated":2,"fields":{"user":"alice"}},` +
+				`"fields":{"user":"alice"},"code":"NotFound"}`,
+		},
+		"without trace": {
+			format: eris.NewDefaultFormat(false),
+			output: `{"version":1,` +
+				`"chain":[{"message":"additional context","code":"NotFound"}],` +
+				`"root":{"message":"root error","fields":{"user":"alice"}},` +
+				`"fields":{"user":"alice"},"code":"NotFound"}`,
+		},
+		"full names": {
+			format: eris.Format{WithTrace: true, FrameOptions: eris.FrameOptions{FullNames: true}},
+			output: `{"version":1,` +
+				`"chain":[{"message":"additional context",` +
+				`"frame":{"function":"example.com/project/api.(*Server).GetResource","file":"api/api.go","line":30},` +
+				`"code":"NotFound"}],` +
+				`"root":{"message":"root error",` +
+				`"stack":[{"function":"example.com/project/db.Get","file":"db/db.go","line":99}],` +
+				`"truncated":2,"fields":{"user":"alice"}},` +
+				`"fields":{"user":"alice"},"code":"NotFound"}`,
+		},
+	}
+
+	for desc, tc := range tests {
+		t.Run(desc, func(t *testing.T) {
+			data, err := json.Marshal(uErr.ToJSONError(tc.format))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(data) != tc.output {
+				t.Errorf("ToJSONError() = %s, want %v", data, tc.output)
+			}
+		})
+	}
+}
+
+// validateSchema validates a decoded JSON document against the subset of JSON Schema used by eris.JSONSchema.
+func validateSchema(root, schema map[string]interface{}, doc interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		def, ok := root["definitions"].(map[string]interface{})[strings.TrimPrefix(ref, "#/definitions/")]
+		if !ok {
+			return fmt.Errorf("%v: unknown reference %v", path, ref)
+		}
+		return validateSchema(root, def.(map[string]interface{}), doc, path)
+	}
+	if typ, ok := schema["type"].(string); ok {
+		var valid bool
+		switch typ {
+		case "object":
+			_, valid = doc.(map[string]interface{})
+		case "array":
+			_, valid = doc.([]interface{})
+		case "string":
+			_, valid = doc.(string)
+		case "integer":
+			n, ok := doc.(float64)
+			valid = ok && n == float64(int64(n))
+		default:
+			return fmt.Errorf("%v: unsupported type %v", path, typ)
+		}
+		if !valid {
+			return fmt.Errorf("%v: expected %v, got %v", path, typ, doc)
+		}
+	}
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, doc) {
+		return fmt.Errorf("%v: expected %v, got %v", path, c, doc)
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		var found bool
+		for _, v := range enum {
+			found = found || reflect.DeepEqual(v, doc)
+		}
+		if !found {
+			return fmt.Errorf("%v: unexpected value %v", path, doc)
+		}
+	}
+	if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(doc.(string)) {
+		return fmt.Errorf("%v: %v doesn't match %v", path, doc, pattern)
+	}
+	if min, ok := schema["minimum"].(float64); ok && doc.(float64) < min {
+		return fmt.Errorf("%v: %v is lower than %v", path, doc, min)
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		var errs []string
+		for _, sub := range anyOf {
+			if err := validateSchema(root, sub.(map[string]interface{}), doc, path); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) == len(anyOf) {
+			return fmt.Errorf("%v: no matching schema (%v)", path, strings.Join(errs, "; "))
+		}
+	}
+	if obj, ok := doc.(map[string]interface{}); ok {
+		props, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, key := range required {
+				if _, ok := obj[key.(string)]; !ok {
+					return fmt.Errorf("%v: missing property %v", path, key)
+				}
+			}
+		}
+		for key, value := range obj {
+			prop, ok := props[key]
+			if !ok {
+				if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+					return fmt.Errorf("%v: unexpected property %v", path, key)
+				}
+				continue
+			}
+			if err := validateSchema(root, prop.(map[string]interface{}), value, path+"."+key); err != nil {
+				return err
+			}
+		}
+	}
+	if arr, ok := doc.([]interface{}); ok {
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range arr {
+				if err := validateSchema(root, items, item, fmt.Sprintf("%v[%v]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
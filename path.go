@@ -0,0 +1,175 @@
+package eris
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// PathRewriter returns the file path to show for a stack frame. The second return value reports whether the
+// rewriter applies to the frame.
+type PathRewriter func(frame StackFrame) (file string, ok bool)
+
+// RewritePrefix returns a PathRewriter that replaces the given prefix of file paths with replacement.
+func RewritePrefix(prefix, replacement string) PathRewriter {
+	return func(frame StackFrame) (string, bool) {
+		if !strings.HasPrefix(frame.File, prefix) {
+			return "", false
+		}
+		return replacement + frame.File[len(prefix):], true
+	}
+}
+
+// TrimGOROOT is a PathRewriter that replaces the GOROOT directory of the Go installation that built the program
+// with "$GOROOT".
+func TrimGOROOT(frame StackFrame) (string, bool) {
+	goroot := gorootDir()
+	if goroot == "" || !strings.HasPrefix(frame.File, goroot+"/") {
+		return "", false
+	}
+	return "$GOROOT" + frame.File[len(goroot):], true
+}
+
+// TrimModulePaths is a PathRewriter that makes file paths relative to the root of the module they belong to, based
+// on the module information embedded in the program (see debug.ReadBuildInfo). Paths of the main module are
+// relative to its root directory (e.g. "api/api.go") and paths of dependencies are prefixed with the module path
+// and version (e.g. "github.com/rotisserie/eris@v0.1.1/eris.go").
+func TrimModulePaths(frame StackFrame) (string, bool) {
+	key := frame.Package + "\x00" + frame.File
+	if file, ok := modulePathCache.Load(key); ok {
+		return file.(string), file.(string) != ""
+	}
+	file := trimModulePath(frame)
+	modulePathCache.Store(key, file)
+	return file, file != ""
+}
+
+// DefaultPathRewriters returns the rewriters applied to stack frame file paths unless they're replaced via
+// SetPathRewriters.
+func DefaultPathRewriters() []PathRewriter {
+	return []PathRewriter{
+		TrimGOROOT,
+		TrimModulePaths,
+	}
+}
+
+var pathRewriters atomic.Value // []PathRewriter
+
+func init() {
+	pathRewriters.Store(DefaultPathRewriters())
+}
+
+// SetPathRewriters replaces the rewriters applied to the file paths of stack frames. For each frame, the first
+// rewriter that applies is used. Calling it without any rewriters shows the file paths recorded at build time.
+//
+// Rewriting happens when errors are unpacked, so the same paths are used by UnpackedError.ToString,
+// UnpackedError.ToJSON, and the %+v verb.
+//
+//	eris.SetPathRewriters(append(
+//		[]eris.PathRewriter{eris.RewritePrefix("/home/ci/src/", "")},
+//		eris.DefaultPathRewriters()...,
+//	)...)
+func SetPathRewriters(rewriters ...PathRewriter) {
+	pathRewriters.Store(append([]PathRewriter(nil), rewriters...))
+}
+
+// rewritePath applies the first matching path rewriter to the frame's file path. The path recorded at build time
+// is kept on the frame for formats that link to the file.
+func rewritePath(frame *StackFrame) {
+	for _, rewrite := range pathRewriters.Load().([]PathRewriter) {
+		if file, ok := rewrite(*frame); ok {
+			if file != frame.File {
+				frame.path = frame.File
+			}
+			frame.File = file
+			return
+		}
+	}
+}
+
+// modulePathCache maps package paths and file names to module-relative paths ("" if there isn't one).
+var modulePathCache sync.Map // map[string]string
+
+var (
+	buildInfo     *debug.BuildInfo
+	buildInfoOnce sync.Once
+)
+
+// loadBuildInfo reads the module information embedded in the program once.
+func loadBuildInfo() {
+	buildInfoOnce.Do(func() {
+		buildInfo, _ = debug.ReadBuildInfo()
+	})
+}
+
+func trimModulePath(frame StackFrame) string {
+	loadBuildInfo()
+	if buildInfo == nil {
+		return ""
+	}
+
+	pkg := frame.Package
+	if pkg == "main" {
+		pkg = buildInfo.Path
+	}
+	pkg = strings.TrimSuffix(pkg, "_test") // external test packages live next to the package under test
+	mod, version := findModule(pkg)
+	if mod == "" {
+		return ""
+	}
+
+	// the directory of the file corresponds to the package path, so the module root is what's left after removing
+	// the package path relative to the module
+	i := strings.LastIndex(frame.File, "/")
+	if i < 0 {
+		return ""
+	}
+	dir, rel := frame.File[:i], pkg[len(mod):]
+	if !strings.HasSuffix(dir, rel) {
+		return ""
+	}
+	root := dir[:len(dir)-len(rel)]
+	if mod == buildInfo.Main.Path {
+		return frame.File[len(root)+1:]
+	}
+	return mod + "@" + version + frame.File[len(root):]
+}
+
+// findModule returns the path and version of the module containing the given package.
+func findModule(pkg string) (string, string) {
+	var mod, version string
+	check := func(m *debug.Module) {
+		if len(m.Path) > len(mod) && (pkg == m.Path || strings.HasPrefix(pkg, m.Path+"/")) {
+			mod, version = m.Path, m.Version
+			if m.Replace != nil && m.Replace.Version != "" {
+				version = m.Replace.Version
+			}
+		}
+	}
+	check(&buildInfo.Main)
+	for _, dep := range buildInfo.Deps {
+		check(dep)
+	}
+	return mod, version
+}
+
+var (
+	goroot     string
+	gorootOnce sync.Once
+)
+
+// gorootDir returns the GOROOT directory recorded in the program's standard library frames. It's empty if the
+// program was built with -trimpath.
+func gorootDir() string {
+	gorootOnce.Do(func() {
+		var pcs [1]uintptr
+		runtime.Callers(0, pcs[:])
+		rFrame, _ := runtime.CallersFrames(pcs[:]).Next()
+		if i := strings.LastIndex(rFrame.File, "/src/runtime/"); i >= 0 {
+			goroot = rFrame.File[:i]
+		}
+	})
+	return goroot
+}
@@ -0,0 +1,58 @@
+package eris_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func TestPathRewriters(t *testing.T) {
+	tests := map[string]struct {
+		rewriters []eris.PathRewriter    // rewriters (defaults if nil)
+		file      func(file string) bool // check for the path of the first frame
+		goroot    func(file string) bool // check for the path of the testing.tRunner frame
+	}{
+		"default rewriters": {
+			file:   func(file string) bool { return file == "path_test.go" },
+			goroot: func(file string) bool { return file == "$GOROOT/src/testing/testing.go" },
+		},
+		"no rewriters": {
+			rewriters: []eris.PathRewriter{},
+			file:      func(file string) bool { return filepath.IsAbs(file) && strings.HasSuffix(file, "/path_test.go") },
+			goroot: func(file string) bool {
+				return filepath.IsAbs(file) && strings.HasSuffix(file, "/src/testing/testing.go")
+			},
+		},
+		"custom rewriters": {
+			rewriters: append([]eris.PathRewriter{eris.RewritePrefix("/", "/build/")}, eris.DefaultPathRewriters()...),
+			file:      func(file string) bool { return strings.HasPrefix(file, "/build/") },
+			goroot:    func(file string) bool { return strings.HasPrefix(file, "/build/") },
+		},
+	}
+
+	for desc, tc := range tests {
+		if tc.rewriters != nil {
+			eris.SetPathRewriters(tc.rewriters...)
+		}
+		err := eris.Wrap(eris.New("root error"), "additional context")
+		uErr := eris.Unpack(err)
+		if file := (*uErr.ErrChain)[0].Frame.File; !tc.file(file) {
+			t.Errorf("%v: unexpected wrap frame path { %v }", desc, file)
+		}
+		if file := uErr.ErrRoot.Stack[0].File; !tc.file(file) {
+			t.Errorf("%v: unexpected root frame path { %v }", desc, file)
+		}
+		for _, frame := range uErr.ErrRoot.Stack {
+			if frame.Name == "testing.tRunner" && !tc.goroot(frame.File) {
+				t.Errorf("%v: unexpected standard library path { %v }", desc, frame.File)
+			}
+		}
+		if str := fmt.Sprintf("%+v", err); !strings.Contains(str, ": "+uErr.ErrRoot.Stack[0].File+": ") {
+			t.Errorf("%v: expected path { %v } in output { %v }", desc, uErr.ErrRoot.Stack[0].File, str)
+		}
+		eris.SetPathRewriters(eris.DefaultPathRewriters()...)
+	}
+}
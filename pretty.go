@@ -0,0 +1,208 @@
+package eris
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ColorMode controls whether PrettyFormat uses ANSI escape codes.
+type ColorMode int
+
+const (
+	// ColorAuto enables colors if the output is a character device, which is how terminals are detected, unless the
+	// NO_COLOR environment variable is set or the TERM environment variable is "dumb".
+	ColorAuto ColorMode = iota
+	// ColorAlways enables colors regardless of the output.
+	ColorAlways
+	// ColorNever disables colors.
+	ColorNever
+)
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiCyan  = "\x1b[36m"
+	ansiReset = "\x1b[0m"
+)
+
+// PrettyFormat is a Formatter for reading errors in a terminal. Messages are printed in bold, function names in
+// color and locations ("file:line") dimmed, and the locations of all stack frames of an error are aligned in a
+// column. Without colors, the output has the same layout in plain text.
+type PrettyFormat struct {
+	WithTrace bool      // Flag that enables stack trace output.
+	Color     ColorMode // Mode that controls the use of colors.
+
+	FrameOptions
+}
+
+// NewPrettyFormat returns a pretty format that uses colors if the output is a terminal. Terminals are detected as
+// character devices, so other devices such as /dev/null count as terminals too. The output is only checked when
+// writing to an *os.File (e.g. os.Stderr) or another writer with a Stat method, not when printing errors via the fmt
+// package, so use ColorAlways with SetFormatter for colored output of fmt.Printf("%+v", err).
+//
+//	eris.NewPrettyFormat(true).FormatError(os.Stderr, eris.Unpack(err))
+func NewPrettyFormat(withTrace bool) PrettyFormat {
+	return PrettyFormat{
+		WithTrace: withTrace,
+		Color:     ColorAuto,
+	}
+}
+
+// FormatError implements Formatter.
+func (pf PrettyFormat) FormatError(w io.Writer, err UnpackedError) error {
+	p := prettyPrinter{
+		format: Format{
+			WithTrace:    pf.WithTrace,
+			FrameOptions: pf.FrameOptions,
+		},
+		color: useColor(pf.Color, w),
+	}
+	if pf.WithTrace {
+		p.writeTrace(&err)
+	} else {
+		p.writeMessages(&err)
+	}
+	_, writeErr := io.WriteString(w, p.b.String())
+	return writeErr
+}
+
+type prettyPrinter struct {
+	b      strings.Builder
+	format Format
+	color  bool
+}
+
+// style writes str wrapped in the given escape code if colors are enabled.
+func (p *prettyPrinter) style(code string, str string) {
+	if p.color && str != "" {
+		p.b.WriteString(code)
+		p.b.WriteString(str)
+		p.b.WriteString(ansiReset)
+		return
+	}
+	p.b.WriteString(str)
+}
+
+func (p *prettyPrinter) writeMessages(upErr *UnpackedError) {
+	if upErr.ErrChain != nil {
+		for _, eLink := range *upErr.ErrChain {
+			if eLink.Type != "" && eLink.Msg == "" {
+				continue
+			}
+			p.style(ansiBold, eLink.Msg)
+			p.b.WriteString(": ")
+		}
+	}
+	if upErr.ErrRoot != nil {
+		p.style(ansiBold, upErr.ErrRoot.Msg)
+	}
+	branches := upErr.branches(false)
+	for i := range branches {
+		if i > 0 {
+			p.b.WriteString("; ")
+		}
+		p.writeMessages(&branches[i])
+	}
+	p.style(ansiBold, upErr.ExternalErr)
+}
+
+func (p *prettyPrinter) writeTrace(upErr *UnpackedError) {
+	var stack []StackFrame
+	if upErr.ErrRoot != nil {
+		stack = filterFrames(upErr.ErrRoot.Stack, p.format.FrameOptions)
+	}
+
+	// align the locations of all frames of this error
+	var width int
+	if upErr.ErrChain != nil {
+		for _, eLink := range *upErr.ErrChain {
+			if eLink.Type == "" {
+				width = maxInt(width, utf8.RuneCountInString(p.format.frameName(eLink.Frame)))
+			}
+		}
+	}
+	for _, sFrame := range stack {
+		width = maxInt(width, utf8.RuneCountInString(p.format.frameName(sFrame)))
+	}
+
+	if upErr.ErrChain != nil {
+		for _, eLink := range *upErr.ErrChain {
+			if eLink.Type != "" && eLink.Msg == "" {
+				continue
+			}
+			p.style(ansiBold, eLink.Msg)
+			p.b.WriteString("\n")
+			if eLink.Type == "" {
+				p.writeFrame(eLink.Frame, width)
+			}
+		}
+	}
+	if upErr.ErrRoot != nil {
+		p.style(ansiBold, upErr.ErrRoot.Msg)
+		p.b.WriteString("\n")
+		for _, sFrame := range stack {
+			p.writeFrame(sFrame, width)
+		}
+		if upErr.ErrRoot.Truncated > 0 {
+			p.b.WriteString("\t")
+			p.style(ansiDim, formatTruncated(upErr.ErrRoot.Truncated))
+			p.b.WriteString("\n")
+		}
+	}
+	for i := range upErr.ErrBranches {
+		branch := prettyPrinter{format: p.format, color: p.color}
+		branch.writeTrace(&upErr.ErrBranches[i])
+		p.b.WriteString(indent(branch.b.String(), "\t"))
+	}
+	if upErr.ExternalErr != "" {
+		p.style(ansiBold, upErr.ExternalErr)
+		p.b.WriteString("\n")
+	}
+}
+
+func (p *prettyPrinter) writeFrame(sFrame StackFrame, width int) {
+	name := p.format.frameName(sFrame)
+	p.b.WriteString("\t")
+	p.style(ansiCyan, name)
+	p.b.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(name)+2))
+	p.style(ansiDim, sFrame.File+":"+strconv.Itoa(sFrame.Line))
+	p.b.WriteString("\n")
+}
+
+// useColor reports whether colors should be used for the given mode and output.
+func useColor(mode ColorMode, w io.Writer) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isCharDevice(w)
+}
+
+// isCharDevice reports whether w is a character device. It's used to detect terminals without platform-specific
+// system calls, so it also reports true for other character devices such as /dev/null.
+func isCharDevice(w io.Writer) bool {
+	f, ok := w.(interface{ Stat() (os.FileInfo, error) })
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
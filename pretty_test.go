@@ -0,0 +1,163 @@
+package eris_test
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func wrapWithContext(err error) error {
+	return eris.Wrap(err, "additional context")
+}
+
+func TestPrettyFormat(t *testing.T) {
+	err := wrapWithContext(eris.New("root error"))
+	uErr := eris.Unpack(err)
+
+	tests := map[string]struct {
+		format eris.PrettyFormat // format used for output
+		output []string          // expected output lines
+	}{
+		"plain": {
+			format: eris.PrettyFormat{WithTrace: true, Color: eris.ColorNever},
+			output: []string{
+				"additional context",
+				"\teris_test.wrapWithContext   pretty_test.go:14",
+				"root error",
+				"\teris_test.wrapWithContext   pretty_test.go:14",
+				"\teris_test.TestPrettyFormat  pretty_test.go:18",
+				"",
+			},
+		},
+		"colors": {
+			format: eris.PrettyFormat{WithTrace: true, Color: eris.ColorAlways},
+			output: []string{
+				"\x1b[1madditional context\x1b[0m",
+				"\t\x1b[36meris_test.wrapWithContext\x1b[0m   \x1b[2mpretty_test.go:14\x1b[0m",
+				"\x1b[1mroot error\x1b[0m",
+				"\t\x1b[36meris_test.wrapWithContext\x1b[0m   \x1b[2mpretty_test.go:14\x1b[0m",
+				"\t\x1b[36meris_test.TestPrettyFormat\x1b[0m  \x1b[2mpretty_test.go:18\x1b[0m",
+				"",
+			},
+		},
+		"without trace": {
+			format: eris.PrettyFormat{Color: eris.ColorAlways},
+			output: []string{"\x1b[1madditional context\x1b[0m: \x1b[1mroot error\x1b[0m"},
+		},
+		"plain without trace": {
+			format: eris.NewPrettyFormat(false),
+			output: []string{"additional context: root error"},
+		},
+	}
+
+	for desc, tc := range tests {
+		var b strings.Builder
+		if writeErr := tc.format.FormatError(&b, uErr); writeErr != nil {
+			t.Fatalf("%v: unexpected error: %v", desc, writeErr)
+		}
+		if expected := strings.Join(tc.output, "\n"); b.String() != expected {
+			t.Errorf("%v: expected { %q } got { %q }", desc, expected, b.String())
+		}
+	}
+}
+
+func TestPrettyFormatAlignment(t *testing.T) {
+	err := eris.Wrap(eris.Join(eris.New("root error"), io.EOF), "additional context")
+	format := eris.PrettyFormat{
+		WithTrace:    true,
+		Color:        eris.ColorNever,
+		FrameOptions: eris.FrameOptions{FullNames: true},
+	}
+
+	var b strings.Builder
+	if writeErr := format.FormatError(&b, eris.Unpack(err)); writeErr != nil {
+		t.Fatalf("unexpected error: %v", writeErr)
+	}
+	column := -1
+	for _, line := range strings.Split(b.String(), "\n") {
+		i := strings.Index(line, "pretty_test.go:")
+		if i < 0 || !strings.Contains(line, "github.com/rotisserie/eris_test.") {
+			continue
+		}
+		// frames of branches are indented by an additional tab
+		i -= strings.Count(line, "\t")
+		if column >= 0 && i != column {
+			t.Errorf("expected locations to be aligned in { %v }", b.String())
+		}
+		column = i
+	}
+	if column < 0 {
+		t.Errorf("expected stack frames in { %v }", b.String())
+	}
+}
+
+func TestPrettyFormatColorDetection(t *testing.T) {
+	err := eris.New("root error")
+	format := eris.NewPrettyFormat(false)
+
+	file, fileErr := ioutil.TempFile("", "eris")
+	if fileErr != nil {
+		t.Fatalf("unexpected error: %v", fileErr)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	noColor, hasNoColor := os.LookupEnv("NO_COLOR")
+	defer func() {
+		if hasNoColor {
+			os.Setenv("NO_COLOR", noColor)
+		} else {
+			os.Unsetenv("NO_COLOR")
+		}
+	}()
+
+	tests := map[string]struct {
+		w       io.Writer // output
+		noColor string    // value of NO_COLOR
+		color   bool      // whether colors are expected
+	}{
+		"builder": {
+			w: &strings.Builder{},
+		},
+		"regular file": {
+			w: file,
+		},
+		"regular file with NO_COLOR": {
+			w:       file,
+			noColor: "1",
+		},
+	}
+
+	for desc, tc := range tests {
+		os.Setenv("NO_COLOR", tc.noColor)
+		w := &recordingWriter{w: tc.w}
+		if writeErr := format.FormatError(w, eris.Unpack(err)); writeErr != nil {
+			t.Fatalf("%v: unexpected error: %v", desc, writeErr)
+		}
+		if color := strings.Contains(w.b.String(), "\x1b["); color != tc.color {
+			t.Errorf("%v: expected colors %v got { %q }", desc, tc.color, w.b.String())
+		}
+	}
+}
+
+// recordingWriter records the output written to w and forwards the Stat method of w if there is one.
+type recordingWriter struct {
+	w io.Writer
+	b strings.Builder
+}
+
+func (rw *recordingWriter) Write(p []byte) (int, error) {
+	rw.b.Write(p)
+	return rw.w.Write(p)
+}
+
+func (rw *recordingWriter) Stat() (os.FileInfo, error) {
+	if f, ok := rw.w.(*os.File); ok {
+		return f.Stat()
+	}
+	return nil, os.ErrInvalid
+}
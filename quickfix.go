@@ -0,0 +1,113 @@
+package eris
+
+import (
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// QuickfixFormat is a Formatter that prints stack traces in the style of compiler diagnostics, which editors such as
+// vim, emacs and VS Code can jump to. Each stack frame is printed on its own line as "file:line: function", and the
+// messages of the chain are appended to the lines of the frames they were created at, so the root error's message is
+// on the line of the innermost frame of its stack trace:
+//
+//	api/api.go:30: api.GetResource: error getting resource 'example-id'
+//	db/db.go:99: db.Get: not found
+//	api/api.go:30: api.GetResource
+//
+// Messages of errors without stack frames, such as external errors, are printed on lines of their own.
+type QuickfixFormat struct {
+	Hyperlinks   bool                               // Flag that enables OSC 8 terminal hyperlinks for locations.
+	HyperlinkURL func(path string, line int) string // Function returning hyperlink URLs (file URLs if nil).
+	FullPaths    bool                               // Flag that enables the file paths recorded at build time.
+
+	FrameOptions
+}
+
+// FormatError implements Formatter.
+func (qf QuickfixFormat) FormatError(w io.Writer, err UnpackedError) error {
+	var b strings.Builder
+	qf.write(&b, &err)
+	_, writeErr := io.WriteString(w, b.String())
+	return writeErr
+}
+
+func (qf QuickfixFormat) write(b *strings.Builder, upErr *UnpackedError) {
+	if upErr.ErrChain != nil {
+		for _, eLink := range *upErr.ErrChain {
+			if eLink.Type != "" {
+				if eLink.Msg != "" {
+					b.WriteString(eLink.Msg + "\n")
+				}
+				continue
+			}
+			qf.writeLine(b, eLink.Frame, eLink.Msg)
+		}
+	}
+	if upErr.ErrRoot != nil {
+		stack := filterFrames(upErr.ErrRoot.Stack, qf.FrameOptions)
+		if len(stack) == 0 {
+			b.WriteString(upErr.ErrRoot.Msg + "\n")
+		}
+		for i, sFrame := range stack {
+			if i == 0 {
+				qf.writeLine(b, sFrame, upErr.ErrRoot.Msg)
+			} else {
+				qf.writeLine(b, sFrame, "")
+			}
+		}
+		if upErr.ErrRoot.Truncated > 0 {
+			b.WriteString(formatTruncated(upErr.ErrRoot.Truncated) + "\n")
+		}
+	}
+	for i := range upErr.ErrBranches {
+		qf.write(b, &upErr.ErrBranches[i])
+	}
+	if upErr.ExternalErr != "" {
+		b.WriteString(upErr.ExternalErr + "\n")
+	}
+}
+
+// writeLine writes the location and function name of a stack frame followed by msg, if any. Frames without a
+// location, e.g. in errors unpacked without stack traces, only show the message.
+func (qf QuickfixFormat) writeLine(b *strings.Builder, sFrame StackFrame, msg string) {
+	if sFrame.File == "" {
+		b.WriteString(msg + "\n")
+		return
+	}
+	file := sFrame.File
+	if qf.FullPaths {
+		file = sFrame.buildPath()
+	}
+	location := file + ":" + strconv.Itoa(sFrame.Line)
+	if qf.Hyperlinks {
+		location = qf.hyperlink(location, sFrame)
+	}
+	b.WriteString(location + ": " + qf.frameName(sFrame))
+	if msg != "" {
+		b.WriteString(": " + msg)
+	}
+	b.WriteString("\n")
+}
+
+// hyperlink wraps text in an OSC 8 hyperlink to the location of the stack frame. The text is returned as is if the
+// absolute path of the file is unknown, e.g. for frames decoded from JSON.
+func (qf QuickfixFormat) hyperlink(text string, sFrame StackFrame) string {
+	path := sFrame.buildPath()
+	if !filepath.IsAbs(path) {
+		return text
+	}
+	var link string
+	if qf.HyperlinkURL != nil {
+		link = qf.HyperlinkURL(path, sFrame.Line)
+	} else {
+		slashPath := filepath.ToSlash(path)
+		if !strings.HasPrefix(slashPath, "/") {
+			slashPath = "/" + slashPath // Windows paths start with a drive letter
+		}
+		link = (&url.URL{Scheme: "file", Path: slashPath}).String()
+	}
+	return "\x1b]8;;" + link + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
@@ -0,0 +1,170 @@
+package eris_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func TestQuickfixFormat(t *testing.T) {
+	err := wrapWithContext(eris.New("root error"))
+	uErr := eris.Unpack(err)
+
+	wd, wdErr := os.Getwd()
+	if wdErr != nil {
+		t.Fatalf("unexpected error: %v", wdErr)
+	}
+	dir := filepath.ToSlash(wd) + "/"
+	dirURL := "file://" + dir
+	if !strings.HasPrefix(dir, "/") {
+		dirURL = "file:///" + dir
+	}
+
+	tests := map[string]struct {
+		input  eris.UnpackedError  // input error
+		format eris.QuickfixFormat // format used for output
+		output []string            // expected output lines
+	}{
+		"trace": {
+			input: uErr,
+			output: []string{
+				"pretty_test.go:14: eris_test.wrapWithContext: additional context",
+				"pretty_test.go:14: eris_test.wrapWithContext: root error",
+				"quickfix_test.go:15: eris_test.TestQuickfixFormat",
+				"",
+			},
+		},
+		"full paths": {
+			input:  uErr,
+			format: eris.QuickfixFormat{FullPaths: true},
+			output: []string{
+				dir + "pretty_test.go:14: eris_test.wrapWithContext: additional context",
+				dir + "pretty_test.go:14: eris_test.wrapWithContext: root error",
+				dir + "quickfix_test.go:15: eris_test.TestQuickfixFormat",
+				"",
+			},
+		},
+		"hyperlinks": {
+			input:  eris.Unpack(eris.New("root error")),
+			format: eris.QuickfixFormat{Hyperlinks: true},
+			output: []string{
+				"\x1b]8;;" + dirURL + "quickfix_test.go\x1b\\quickfix_test.go:53\x1b]8;;\x1b\\: " +
+					"eris_test.TestQuickfixFormat: root error",
+				"",
+			},
+		},
+		"custom hyperlinks": {
+			input: eris.Unpack(wrapWithContext(io.EOF)),
+			format: eris.QuickfixFormat{
+				Hyperlinks: true,
+				HyperlinkURL: func(path string, line int) string {
+					return fmt.Sprintf("vscode://file/%v:%v", filepath.Base(path), line)
+				},
+			},
+			output: []string{
+				"\x1b]8;;vscode://file/pretty_test.go:14\x1b\\pretty_test.go:14\x1b]8;;\x1b\\: " +
+					"eris_test.wrapWithContext: additional context",
+				"\x1b]8;;vscode://file/pretty_test.go:14\x1b\\pretty_test.go:14\x1b]8;;\x1b\\: " +
+					"eris_test.wrapWithContext: EOF",
+				"\x1b]8;;vscode://file/quickfix_test.go:62\x1b\\quickfix_test.go:62\x1b]8;;\x1b\\: " +
+					"eris_test.TestQuickfixFormat",
+				"",
+			},
+		},
+		"external error in the chain": {
+			input: eris.Unpack(fmt.Errorf("external context: %w", eris.New("root error"))),
+			output: []string{
+				"external context",
+				"quickfix_test.go:80: eris_test.TestQuickfixFormat: root error",
+				"",
+			},
+		},
+		"without trace": {
+			input: eris.UnpackedError{
+				ErrChain: &[]eris.ErrLink{{Msg: "additional context"}},
+				ErrRoot:  &eris.ErrRoot{Msg: "root error"},
+			},
+			output: []string{"additional context", "root error", ""},
+		},
+		"decoded frames": {
+			input: eris.UnpackedError{
+				ErrRoot: &eris.ErrRoot{
+					Msg:   "root error",
+					Stack: []eris.StackFrame{{Name: "api.GetResource", File: "api/api.go", Line: 30}},
+				},
+			},
+			format: eris.QuickfixFormat{Hyperlinks: true, FullPaths: true},
+			output: []string{"api/api.go:30: api.GetResource: root error", ""},
+		},
+		"truncated": {
+			input: eris.UnpackedError{
+				ErrRoot: &eris.ErrRoot{
+					Msg:       "root error",
+					Stack:     []eris.StackFrame{{Name: "api.GetResource", File: "api/api.go", Line: 30}},
+					Truncated: 2,
+				},
+			},
+			output: []string{"api/api.go:30: api.GetResource: root error", "... 2 more frames", ""},
+		},
+		"full names without test frames": {
+			input: uErr,
+			format: eris.QuickfixFormat{FrameOptions: eris.FrameOptions{
+				Filters: []eris.FrameFilter{func(frame eris.StackFrame) bool {
+					return strings.HasPrefix(frame.Function, "TestQuickfixFormat")
+				}},
+				FullNames: true,
+			}},
+			output: []string{
+				"pretty_test.go:14: github.com/rotisserie/eris_test.wrapWithContext: additional context",
+				"pretty_test.go:14: github.com/rotisserie/eris_test.wrapWithContext: root error",
+				"",
+			},
+		},
+	}
+
+	for desc, tc := range tests {
+		var b strings.Builder
+		if writeErr := tc.format.FormatError(&b, tc.input); writeErr != nil {
+			t.Fatalf("%v: unexpected error: %v", desc, writeErr)
+		}
+		if expected := strings.Join(tc.output, "\n"); b.String() != expected {
+			t.Errorf("%v: expected { %q } got { %q }", desc, expected, b.String())
+		}
+	}
+}
+
+func TestQuickfixFormatRewrittenPaths(t *testing.T) {
+	// every file is rewritten to the same path, so the build-time paths can only be found via the frames
+	eris.SetPathRewriters(func(eris.StackFrame) (string, bool) { return "main.go", true })
+	defer eris.SetPathRewriters(eris.DefaultPathRewriters()...)
+	uErr := eris.Unpack(wrapWithContext(eris.New("root error")))
+
+	wd, wdErr := os.Getwd()
+	if wdErr != nil {
+		t.Fatalf("unexpected error: %v", wdErr)
+	}
+	dir := filepath.ToSlash(wd) + "/"
+
+	var b strings.Builder
+	if writeErr := (eris.QuickfixFormat{FullPaths: true}).FormatError(&b, uErr); writeErr != nil {
+		t.Fatalf("unexpected error: %v", writeErr)
+	}
+	expected := []string{
+		dir + "pretty_test.go:14: eris_test.wrapWithContext: additional context",
+		dir + "pretty_test.go:14: eris_test.wrapWithContext: root error",
+		dir + "quickfix_test.go:145: eris_test.TestQuickfixFormatRewrittenPaths",
+	}
+	for _, line := range expected {
+		if !strings.Contains(b.String(), line+"\n") {
+			t.Errorf("expected line { %v } in { %v }", line, b.String())
+		}
+	}
+	if str := uErr.ToString(eris.NewDefaultFormat(true)); !strings.Contains(str, "main.go") {
+		t.Errorf("expected rewritten paths in { %v }", str)
+	}
+}
@@ -0,0 +1,117 @@
+package eris
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+// Recover recovers from a panic and stores it in err as an eris error, as returned by RecoverPanic. It has no effect
+// if the goroutine isn't panicking. Recover must be deferred directly rather than called from a deferred function,
+// since it calls recover itself, and any error already stored in err is replaced.
+//
+//	func GetResource(id string) (res *Resource, err error) {
+//		defer eris.Recover(&err)
+//		...
+//	}
+func Recover(err *error) {
+	if r := recover(); r != nil {
+		*err = recoverPanic(r, 4)
+	}
+}
+
+// RecoverPanic converts a value returned by recover into an eris error and returns nil if the value is nil. The error
+// has the stack trace of the panicking goroutine at the point of the panic if RecoverPanic is called from a deferred
+// function, which is where recover is called.
+//
+// If the panic value is an error, it's kept in the chain so eris.Is and eris.As find it: eris errors are wrapped with
+// the message "panic", and other errors are turned into root errors with their message prefixed with "panic: ". Any
+// other value is turned into a root error with its formatted value as the message, prefixed with "panic: ".
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			err = eris.RecoverPanic(r)
+//		}
+//	}()
+func RecoverPanic(r interface{}) error {
+	return recoverPanic(r, 4)
+}
+
+func recoverPanic(r interface{}, skip int) error {
+	if r == nil {
+		return nil
+	}
+	stack := panicStack(skip)
+	switch e := r.(type) {
+	case *rootError:
+		return &wrapError{
+			msg:   "panic",
+			err:   e.copy(stack),
+			frame: stack.frame(),
+		}
+	case error:
+		if hasErisError(e) {
+			return &wrapError{
+				msg:   "panic",
+				err:   e,
+				frame: stack.frame(),
+			}
+		}
+		return &rootError{
+			msg:   "panic: " + e.Error(),
+			ext:   e,
+			stack: stack,
+		}
+	default:
+		return &rootError{
+			msg:   fmt.Sprintf("panic: %v", r),
+			stack: stack,
+		}
+	}
+}
+
+// panicStack returns the stack trace of the panicking goroutine at the point of the panic if it's called while the
+// goroutine is panicking, i.e. from a deferred function. The frames of the deferred functions and the runtime frames
+// that raised the panic are left out. Otherwise, panicStack returns the same stack trace as callers. The argument skip
+// is the same as for callersDepth.
+func panicStack(skip int) *stack {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(skip, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+	for i, pc := range pcs {
+		if !isRuntimeFrame(pc, "gopanic") {
+			continue
+		}
+		// runtime errors such as nil pointer dereferences are raised by further runtime functions
+		for i++; i < len(pcs) && isRuntimeFrame(pcs[i], ""); i++ {
+		}
+		pcs = pcs[i:]
+		break
+	}
+
+	depth := int(atomic.LoadInt32(&maxStackDepth))
+	if len(pcs) > depth {
+		return &stack{
+			pcs:       pcs[:depth],
+			truncated: len(pcs) - depth,
+		}
+	}
+	return &stack{pcs: pcs}
+}
+
+// isRuntimeFrame reports whether the program counter is located in the given function of the runtime package, or
+// in any function of the runtime package if function is empty.
+func isRuntimeFrame(pc uintptr, function string) bool {
+	for _, sFrame := range symbolize(pc) {
+		if sFrame.Package == "runtime" && (function == "" || sFrame.Function == function) {
+			return true
+		}
+	}
+	return false
+}
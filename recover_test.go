@@ -0,0 +1,131 @@
+package eris_test
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func panicker(v interface{}) {
+	panic(v)
+}
+
+func recoverPanic(v interface{}) (err error) {
+	defer eris.Recover(&err)
+	panicker(v)
+	return nil
+}
+
+func recoverPanicValue(v interface{}) (err error) {
+	defer func() {
+		err = eris.RecoverPanic(recover())
+	}()
+	panicker(v)
+	return nil
+}
+
+func nilMapPanic() (err error) {
+	defer eris.Recover(&err)
+	var m map[string]int
+	m["key"] = 1
+	return nil
+}
+
+func nilPointerPanic() (err error) {
+	defer eris.Recover(&err)
+	var p *struct{ n int }
+	p.n = 1
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	globalErr := eris.New("global error")
+
+	tests := map[string]struct {
+		input   error  // error returned by a panicking function
+		output  string // expected output
+		is      error  // error expected to be in the chain
+		runtime bool   // whether a runtime.Error is expected to be in the chain
+		frame   string // expected first frame of the root stack trace
+	}{
+		"string": {
+			input:  recoverPanic("something went wrong"),
+			output: "panic: something went wrong",
+			frame:  "eris_test.panicker",
+		},
+		"other value": {
+			input:  recoverPanic(42),
+			output: "panic: 42",
+			frame:  "eris_test.panicker",
+		},
+		"external error": {
+			input:  recoverPanic(io.EOF),
+			output: "panic: EOF",
+			is:     io.EOF,
+			frame:  "eris_test.panicker",
+		},
+		"eris error": {
+			input:  recoverPanic(globalErr),
+			output: "panic: global error",
+			is:     globalErr,
+			frame:  "eris_test.panicker",
+		},
+		"wrapped eris error": {
+			input:  recoverPanicValue(eris.Wrap(globalErr, "additional context")),
+			output: "panic: additional context: global error",
+			is:     globalErr,
+			frame:  "eris_test.TestRecover",
+		},
+		"recovered value": {
+			input:  recoverPanicValue("something went wrong"),
+			output: "panic: something went wrong",
+			frame:  "eris_test.panicker",
+		},
+		"runtime error": {
+			input:   nilMapPanic(),
+			output:  "panic: assignment to entry in nil map",
+			runtime: true,
+			frame:   "eris_test.nilMapPanic",
+		},
+		"signal": {
+			input:   nilPointerPanic(),
+			output:  "panic: runtime error: invalid memory address or nil pointer dereference",
+			runtime: true,
+			frame:   "eris_test.nilPointerPanic",
+		},
+	}
+
+	for desc, tc := range tests {
+		if tc.input == nil {
+			t.Errorf("%v: expected an error", desc)
+			continue
+		}
+		if tc.input.Error() != tc.output {
+			t.Errorf("%v: expected { %v } got { %v }", desc, tc.output, tc.input)
+		}
+		if tc.is != nil && !eris.Is(tc.input, tc.is) {
+			t.Errorf("%v: expected { %v } to be in the chain of { %v }", desc, tc.is, tc.input)
+		}
+		var runtimeErr runtime.Error
+		if ok := eris.As(tc.input, &runtimeErr); ok != tc.runtime {
+			t.Errorf("%v: expected eris.As(runtime.Error) to return %v but got %v", desc, tc.runtime, ok)
+		}
+		stack := eris.StackTrace(tc.input)
+		if len(stack) == 0 || stack[0].Name != tc.frame {
+			t.Errorf("%v: expected stack trace starting with { %v } got { %v }", desc, tc.frame, stack)
+		}
+	}
+}
+
+func TestRecoverPanicWithoutPanic(t *testing.T) {
+	if err := eris.RecoverPanic(nil); err != nil {
+		t.Errorf("expected nil but got { %v }", err)
+	}
+	err := eris.RecoverPanic(errors.New("external error"))
+	if stack := eris.StackTrace(err); len(stack) == 0 || stack[0].Name != "eris_test.TestRecoverPanicWithoutPanic" {
+		t.Errorf("expected stack trace starting in the caller { %v }", stack)
+	}
+}
@@ -0,0 +1,185 @@
+//go:build go1.21
+// +build go1.21
+
+package eris
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strconv"
+)
+
+// LogValue implements slog.LogValuer. The error is logged as a group containing its message, code, fields, error
+// chain, and root error, using the same keys as UnpackedError.ToJSONError with lists logged as groups keyed by their
+// index (e.g. "chain.0.message"). Stack traces aren't included since symbolizing them on every log call is
+// expensive; use NewSlogHandler to log them.
+func (e *rootError) LogValue() slog.Value {
+	return logValue(e, false)
+}
+
+// LogValue implements slog.LogValuer. See rootError.LogValue.
+func (e *wrapError) LogValue() slog.Value {
+	return logValue(e, false)
+}
+
+// LogValue implements slog.LogValuer. See rootError.LogValue.
+func (e *joinError) LogValue() slog.Value {
+	return logValue(e, false)
+}
+
+// logValue returns the structured representation of err for log/slog. It only consists of groups and scalar values,
+// so handlers other than slog.JSONHandler log every part of the error as an attribute of its own.
+func logValue(err error, withTrace bool) slog.Value {
+	uErr := unpack(err, withTrace)
+	jsonErr := uErr.toJSONError(NewDefaultFormat(withTrace))
+
+	attrs := []slog.Attr{slog.String("message", err.Error())}
+	return slog.GroupValue(append(attrs, errorAttrs(jsonErr)...)...)
+}
+
+func errorAttrs(jsonErr JSONError) []slog.Attr {
+	attrs := metaAttrs(nil, jsonErr.Fields, jsonErr.Code)
+	if len(jsonErr.Chain) > 0 {
+		links := make([]slog.Attr, 0, len(jsonErr.Chain))
+		for i, link := range jsonErr.Chain {
+			links = append(links, slog.Attr{Key: strconv.Itoa(i), Value: linkValue(link)})
+		}
+		attrs = append(attrs, slog.Attr{Key: "chain", Value: slog.GroupValue(links...)})
+	}
+	if jsonErr.Root != nil {
+		attrs = append(attrs, slog.Attr{Key: "root", Value: rootValue(jsonErr.Root)})
+	}
+	if jsonErr.External != "" {
+		attrs = append(attrs, slog.String("external", jsonErr.External), slog.String("externalType", jsonErr.ExternalType))
+	}
+	if len(jsonErr.Branches) > 0 {
+		branches := make([]slog.Attr, 0, len(jsonErr.Branches))
+		for i, branch := range jsonErr.Branches {
+			branches = append(branches, slog.Attr{Key: strconv.Itoa(i), Value: slog.GroupValue(errorAttrs(branch)...)})
+		}
+		attrs = append(attrs, slog.Attr{Key: "branches", Value: slog.GroupValue(branches...)})
+	}
+	return attrs
+}
+
+func linkValue(link JSONLink) slog.Value {
+	attrs := []slog.Attr{slog.String("message", link.Message)}
+	if link.Frame != nil {
+		attrs = append(attrs, slog.Attr{Key: "frame", Value: frameValue(*link.Frame)})
+	}
+	if link.Type != "" {
+		attrs = append(attrs, slog.String("type", link.Type))
+	}
+	return slog.GroupValue(metaAttrs(attrs, link.Fields, link.Code)...)
+}
+
+func rootValue(root *JSONRoot) slog.Value {
+	attrs := []slog.Attr{slog.String("message", root.Message)}
+	if len(root.Stack) > 0 {
+		frames := make([]slog.Attr, 0, len(root.Stack))
+		for i, frame := range root.Stack {
+			frames = append(frames, slog.Attr{Key: strconv.Itoa(i), Value: frameValue(frame)})
+		}
+		attrs = append(attrs, slog.Attr{Key: "stack", Value: slog.GroupValue(frames...)})
+	}
+	if root.Truncated > 0 {
+		attrs = append(attrs, slog.Int("truncated", root.Truncated))
+	}
+	return slog.GroupValue(metaAttrs(attrs, root.Fields, root.Code)...)
+}
+
+func frameValue(frame JSONFrame) slog.Value {
+	return slog.GroupValue(
+		slog.String("function", frame.Function),
+		slog.String("file", frame.File),
+		slog.Int("line", frame.Line),
+	)
+}
+
+// metaAttrs appends the code, unless it's OK, and the fields sorted by key to attrs.
+func metaAttrs(attrs []slog.Attr, fields map[string]interface{}, code Code) []slog.Attr {
+	if code != OK {
+		attrs = append(attrs, slog.String("code", code.String()))
+	}
+	if len(fields) > 0 {
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		group := make([]slog.Attr, 0, len(keys))
+		for _, key := range keys {
+			group = append(group, slog.Any(key, fields[key]))
+		}
+		attrs = append(attrs, slog.Attr{Key: "fields", Value: slog.GroupValue(group...)})
+	}
+	return attrs
+}
+
+// NewSlogHandler returns a slog.Handler that expands every error-valued attribute before passing records to h. Errors
+// of any type are unpacked via eris.Unpack and logged with the same structure as eris errors logged via their
+// LogValue method, and stack traces are only included if withTrace is set.
+//
+//	logger := slog.New(eris.NewSlogHandler(slog.NewJSONHandler(os.Stderr, nil), true))
+//	logger.Error("error getting resource", "error", err)
+func NewSlogHandler(h slog.Handler, withTrace bool) slog.Handler {
+	return &slogHandler{
+		handler:   h,
+		withTrace: withTrace,
+	}
+}
+
+type slogHandler struct {
+	handler   slog.Handler
+	withTrace bool
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(attr slog.Attr) bool {
+		expanded.AddAttrs(h.expand(attr))
+		return true
+	})
+	return h.handler.Handle(ctx, expanded)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		expanded = append(expanded, h.expand(attr))
+	}
+	return &slogHandler{
+		handler:   h.handler.WithAttrs(expanded),
+		withTrace: h.withTrace,
+	}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{
+		handler:   h.handler.WithGroup(name),
+		withTrace: h.withTrace,
+	}
+}
+
+// expand replaces error values in attr, including errors in groups, with their structured representation.
+func (h *slogHandler) expand(attr slog.Attr) slog.Attr {
+	switch attr.Value.Kind() {
+	case slog.KindAny, slog.KindLogValuer:
+		if err, ok := attr.Value.Any().(error); ok && err != nil {
+			attr.Value = logValue(err, h.withTrace)
+		}
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		expanded := make([]slog.Attr, 0, len(group))
+		for _, groupAttr := range group {
+			expanded = append(expanded, h.expand(groupAttr))
+		}
+		attr.Value = slog.GroupValue(expanded...)
+	}
+	return attr
+}
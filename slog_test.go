@@ -0,0 +1,178 @@
+//go:build go1.21
+// +build go1.21
+
+package eris_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func TestLogValue(t *testing.T) {
+	globalErr := eris.WithCode(eris.New("global error"), eris.NotFound)
+
+	tests := map[string]struct {
+		input     error                  // input error
+		withTrace bool                   // whether the handler includes stack traces
+		message   string                 // expected message
+		code      string                 // expected code
+		fields    map[string]interface{} // expected fields
+		chain     int                    // expected length of the chain
+		root      bool                   // whether a root error is expected
+		external  string                 // expected external error message
+	}{
+		"root error": {
+			input:     globalErr,
+			withTrace: true,
+			message:   "global error",
+			code:      "NotFound",
+			root:      true,
+		},
+		"wrapped error": {
+			input:     eris.WithFields(eris.Wrap(globalErr, "additional context"), map[string]interface{}{"id": "res-1"}),
+			withTrace: true,
+			message:   "additional context: global error",
+			code:      "NotFound",
+			fields:    map[string]interface{}{"id": "res-1"},
+			chain:     1,
+			root:      true,
+		},
+		"wrapped error without trace": {
+			input:   eris.Wrap(globalErr, "additional context"),
+			message: "additional context: global error",
+			code:    "NotFound",
+			chain:   1,
+			root:    true,
+		},
+		"external error": {
+			input:     io.EOF,
+			withTrace: true,
+			message:   "EOF",
+			external:  "EOF",
+		},
+	}
+
+	for desc, tc := range tests {
+		buf := &bytes.Buffer{}
+		logger := slog.New(eris.NewSlogHandler(slog.NewJSONHandler(buf, nil), tc.withTrace))
+		logger.Error("request failed", "error", tc.input)
+
+		var record struct {
+			Error struct {
+				Message string                   `json:"message"`
+				Code    string                   `json:"code"`
+				Fields  map[string]interface{}   `json:"fields"`
+				Chain   map[string]eris.JSONLink `json:"chain"`
+				Root    *struct {
+					Message string                    `json:"message"`
+					Stack   map[string]eris.JSONFrame `json:"stack"`
+				} `json:"root"`
+				External string `json:"external"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("%v: unexpected error: %v", desc, err)
+		}
+		logged := record.Error
+		if logged.Message != tc.message {
+			t.Errorf("%v: expected message { %v } got { %v }", desc, tc.message, logged.Message)
+		}
+		if logged.Code != tc.code {
+			t.Errorf("%v: expected code { %v } got { %v }", desc, tc.code, logged.Code)
+		}
+		if len(logged.Fields) != len(tc.fields) || (len(tc.fields) > 0 && logged.Fields["id"] != tc.fields["id"]) {
+			t.Errorf("%v: expected fields { %v } got { %v }", desc, tc.fields, logged.Fields)
+		}
+		if len(logged.Chain) != tc.chain {
+			t.Errorf("%v: expected chain of length %v got { %v }", desc, tc.chain, logged.Chain)
+		}
+		if (logged.Root != nil) != tc.root {
+			t.Fatalf("%v: expected root %v got { %v }", desc, tc.root, logged.Root)
+		}
+		if logged.Root != nil && (len(logged.Root.Stack) > 0) != tc.withTrace {
+			t.Errorf("%v: expected stack trace %v got { %v }", desc, tc.withTrace, logged.Root.Stack)
+		}
+		if logged.External != tc.external {
+			t.Errorf("%v: expected external error { %v } got { %v }", desc, tc.external, logged.External)
+		}
+	}
+}
+
+func TestSlogHandlerAttrs(t *testing.T) {
+	err := eris.New("global error")
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(eris.NewSlogHandler(slog.NewJSONHandler(buf, nil), false))
+	logger.With("cause", err).WithGroup("request").Info("done", slog.Group("result", "error", err))
+
+	var record struct {
+		Cause struct {
+			Message string `json:"message"`
+		} `json:"cause"`
+		Request struct {
+			Result struct {
+				Error struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			} `json:"result"`
+		} `json:"request"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Cause.Message != "global error" || record.Request.Result.Error.Message != "global error" {
+		t.Errorf("expected expanded errors got { %s }", buf.Bytes())
+	}
+
+	// without the handler, eris errors are still logged via LogValue, but without stack traces
+	buf.Reset()
+	slog.New(slog.NewJSONHandler(buf, nil)).Error("failed", "error", err)
+	var plain struct {
+		Error struct {
+			Root *struct {
+				Message string                    `json:"message"`
+				Stack   map[string]eris.JSONFrame `json:"stack"`
+			} `json:"root"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &plain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if root := plain.Error.Root; root == nil || root.Message != "global error" || len(root.Stack) != 0 {
+		t.Errorf("expected root error without stack trace got { %s }", buf.Bytes())
+	}
+}
+
+func TestLogValueTextHandler(t *testing.T) {
+	err := eris.WithFields(eris.Wrap(eris.New("root error"), "additional context"), map[string]interface{}{"id": 1})
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(eris.NewSlogHandler(slog.NewTextHandler(buf, nil), true))
+	logger.Error("request failed", "error", err)
+
+	// every part of the error is logged as its own attribute rather than as a formatted Go value
+	out := buf.String()
+	for _, attr := range []string{
+		`error.message="additional context: root error"`,
+		"error.fields.id=1",
+		`error.chain.0.message="additional context"`,
+		"error.chain.0.frame.function=eris_test.TestLogValueTextHandler",
+		"error.chain.0.frame.file=slog_test.go",
+		`error.root.message="root error"`,
+		"error.root.stack.0.function=eris_test.TestLogValueTextHandler",
+		"error.root.stack.0.line=",
+	} {
+		if !strings.Contains(out, " "+attr) {
+			t.Errorf("expected attribute { %v } in output { %v }", attr, out)
+		}
+	}
+	if strings.Contains(out, "{") || strings.Contains(out, "0x") {
+		t.Errorf("expected only structured attributes in output { %v }", out)
+	}
+}
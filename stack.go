@@ -2,77 +2,399 @@ package eris
 
 import (
 	"fmt"
+	"net/url"
+	"reflect"
 	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // StackFrame stores a frame's runtime information in a human readable format.
+//
+// Name is a short display name made of the last element of the package path, the receiver type, and the function
+// name (e.g. "eris.(*rootError).Error"). Closures are shown as part of the function that declares them (e.g.
+// "api.GetResource (closure 1)") and type parameters of generic functions are shown as "[...]". The Package,
+// Receiver, and Function fields contain the individual parts of the name.
 type StackFrame struct {
-	Name string
-	File string
-	Line int
+	Name string `json:"name"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+
+	Package  string `json:"package,omitempty"`  // Import path of the package (e.g. "github.com/rotisserie/eris").
+	Receiver string `json:"receiver,omitempty"` // Receiver type of methods (e.g. "*rootError"), empty for functions.
+	Function string `json:"function,omitempty"` // Function or method name, including closure suffixes (e.g. "Get.func1").
+
+	path string // File path recorded at build time if File was rewritten, empty otherwise.
 }
 
-func (f *StackFrame) formatFrame(sep string) string {
-	return fmt.Sprintf("%v%v%v%v%v", f.Name, sep, f.File, sep, f.Line)
+// buildPath returns the file path recorded at build time, which is only known for frames of errors created in this
+// process.
+func (f *StackFrame) buildPath() string {
+	if f.path != "" {
+		return f.path
+	}
+	return f.File
+}
+
+// FullName returns the name of the frame with the full package path (e.g.
+// "github.com/rotisserie/eris.(*rootError).Error"). Name is returned if the package is unknown.
+func (f *StackFrame) FullName() string {
+	if f.Package == "" {
+		return f.Name
+	}
+	return f.Package + "." + displayName(f.Receiver, f.Function)
+}
+
+func (f *StackFrame) formatFrame(format Format) string {
+	return fmt.Sprintf("%v%v%v%v%v", format.frameName(*f), format.TSep, f.File, format.TSep, f.Line)
+}
+
+// newStackFrame returns a stack frame for the given fully qualified function name as reported by the runtime (e.g.
+// "github.com/rotisserie/eris.(*rootError).Error").
+func newStackFrame(name string, file string, line int) StackFrame {
+	sFrame := StackFrame{
+		Name: name,
+		File: file,
+		Line: line,
+	}
+
+	// the package path ends at the first dot after the last slash, which can't be part of type parameters
+	end := len(name)
+	if i := strings.Index(name, "["); i >= 0 {
+		end = i
+	}
+	slash := strings.LastIndex(name[:end], "/")
+	dot := strings.Index(name[slash+1:end], ".")
+	if dot < 0 {
+		return sFrame
+	}
+	// dots in the last element of the package path are escaped, e.g. "gopkg.in/yaml%2ev3"
+	pkg := name[:slash+1+dot]
+	if unescaped, err := url.PathUnescape(pkg); err == nil {
+		pkg = unescaped
+	}
+	sFrame.Package = pkg
+	sFrame.Receiver, sFrame.Function = splitReceiver(name[slash+1+dot+1:])
+	sFrame.Name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + displayName(sFrame.Receiver, sFrame.Function)
+	return sFrame
+}
+
+// splitReceiver splits the part of a function name following the package into the receiver type and the function.
+func splitReceiver(name string) (string, string) {
+	// pointer receivers are always enclosed in parentheses, e.g. "(*T).Method"
+	if strings.HasPrefix(name, "(") {
+		if i := strings.Index(name, ")."); i >= 0 {
+			return name[1:i], name[i+2:]
+		}
+		return "", name
+	}
+
+	// value receivers are only recognized by the method name following them, e.g. "T.Method" as opposed to
+	// closures such as "Func.func1"
+	elems := splitName(name)
+	n := len(elems)
+	for n > 1 && isClosure(elems[n-1]) {
+		n--
+	}
+	if n < 2 {
+		return "", name
+	}
+	return elems[0], name[len(elems[0])+1:]
+}
+
+// displayName returns the name of a function with closures shown as part of the function declaring them.
+func displayName(receiver, function string) string {
+	elems := splitName(function)
+	n := len(elems)
+	for n > 1 && isClosure(elems[n-1]) {
+		n--
+	}
+	if n < len(elems) && elems[n-1] != "" && strings.HasPrefix(elems[n], "func") {
+		closure := make([]string, 0, len(elems)-n)
+		for _, elem := range elems[n:] {
+			closure = append(closure, strings.TrimPrefix(elem, "func"))
+		}
+		function = strings.Join(elems[:n], ".") + " (closure " + strings.Join(closure, ".") + ")"
+	}
+	if receiver == "" {
+		return function
+	}
+	if strings.HasPrefix(receiver, "*") {
+		return "(" + receiver + ")." + function
+	}
+	return receiver + "." + function
+}
+
+// splitName splits a function name at each dot that isn't part of type parameters.
+func splitName(name string) []string {
+	var elems []string
+	depth, start := 0, 0
+	for i, c := range name {
+		switch c {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				elems = append(elems, name[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(elems, name[start:])
+}
+
+// isClosure reports whether an element of a function name identifies a closure, e.g. "func1" or "2" for nested
+// closures. Empty elements are part of the names of package-level closures, e.g. "glob..func1".
+func isClosure(elem string) bool {
+	elem = strings.TrimPrefix(elem, "func")
+	for _, c := range elem {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
 }
 
 // caller returns a single stack frame. the argument skip is the number of stack frames
 // to ascend, with 0 identifying the caller of Caller.
 func caller(skip int) *frame {
-	pc, _, _, _ := runtime.Caller(skip)
-	var f frame = frame(pc)
-	return &f
+	var pcs [1]uintptr
+	runtime.Callers(skip+1, pcs[:])
+	return &frame{pc: pcs[0]}
+}
+
+// DefaultStackDepth is the default maximum number of frames recorded in the stack trace of a root error.
+const DefaultStackDepth = 64
+
+var maxStackDepth int32 = DefaultStackDepth
+
+// SetMaxStackDepth sets the maximum number of frames recorded in the stack trace of root errors created from now on.
+// Deeper stacks are truncated and the number of omitted frames is reported when the trace is printed. Values lower
+// than 1 restore the default depth. It's safe to call SetMaxStackDepth concurrently with creating errors.
+func SetMaxStackDepth(depth int) {
+	if depth < 1 {
+		depth = DefaultStackDepth
+	}
+	atomic.StoreInt32(&maxStackDepth, int32(depth))
 }
 
-// callers returns a stack trace. the argument skip is the number of stack frames to skip
-// before recording in pc, with 0 identifying the frame for Callers itself and 1 identifying
-// the caller of Callers.
+// callers returns a stack trace with the package-level maximum depth. the argument skip is the number of
+// stack frames to skip before recording in pc, with 0 identifying the frame for Callers itself and 1
+// identifying the caller of Callers.
 func callers(skip int) *stack {
-	const depth = 64
-	var pcs [depth]uintptr
-	n := runtime.Callers(skip, pcs[:])
-	var st stack = pcs[0:n]
-	return &st
+	return callersDepth(skip+1, 0)
 }
 
-// frame is a single program counter of a stack frame.
-type frame uintptr
+// callersDepth returns a stack trace with at most depth frames, or the package-level maximum depth if
+// depth is lower than 1. the argument skip is the same as for callers.
+func callersDepth(skip int, depth int) *stack {
+	if depth < 1 {
+		depth = int(atomic.LoadInt32(&maxStackDepth))
+	}
+	pcs := make([]uintptr, depth+1)
+	n := runtime.Callers(skip, pcs)
+	if n <= depth {
+		return &stack{pcs: pcs[:n]}
+	}
 
-func (f frame) pc() uintptr {
-	return uintptr(f) - 1
+	// count the remaining frames so the truncation can be reported
+	var buf [64]uintptr
+	total := depth
+	for {
+		m := runtime.Callers(skip+total, buf[:])
+		total += m
+		if m < len(buf) {
+			break
+		}
+	}
+	return &stack{
+		pcs:       pcs[:depth],
+		truncated: total - depth,
+	}
+}
+
+// StackTrace returns the stack trace of the root error in err's chain, innermost frame first. For multi-errors, the
+// first branch with a stack trace is used. External errors that expose their stack trace the same way as the errors
+// of github.com/pkg/errors are supported as well. Nil is returned if err doesn't have a stack trace.
+//
+// StackTrace makes eris stack traces available to other libraries without formatting errors as strings. The frames
+// have the same names and file paths as in the output of eris.Unpack, but they aren't filtered.
+func StackTrace(err error) []StackFrame {
+	if root := rootOf(err); root != nil {
+		return root.stack.get()
+	}
+	if pcs := StackPCs(err); pcs != nil {
+		return (&stack{pcs: pcs}).get()
+	}
+	return nil
+}
+
+// StackPCs returns the program counters of the stack trace returned by eris.StackTrace, as returned by
+// runtime.Callers. They can be symbolized via runtime.CallersFrames. Nil is returned if err doesn't have a stack
+// trace or if it was decoded via eris.FromJSON.
+func StackPCs(err error) []uintptr {
+	if root := rootOf(err); root != nil {
+		return append([]uintptr(nil), root.stack.pcs...)
+	}
+	var pcs []uintptr
+	for ; err != nil; err = unwrapCause(err) {
+		if errPCs := stackTracePCs(err); len(errPCs) > 0 {
+			pcs = errPCs
+		}
+	}
+	return pcs
+}
+
+// rootOf returns the first root error in err's chain, following the first branch of multi-errors that contains one.
+func rootOf(err error) *rootError {
+	for ; err != nil; err = unwrapCause(err) {
+		switch e := err.(type) {
+		case *rootError:
+			return e
+		case interface{ Unwrap() []error }:
+			for _, branch := range e.Unwrap() {
+				if root := rootOf(branch); root != nil {
+					return root
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// externalStack returns a stack trace with at most depth frames (or the package-level maximum depth if depth is
+// lower than 1) for an external error, reusing the stack trace recorded by the package that created it. Errors
+// expose their stack traces the same way as the errors of github.com/pkg/errors, i.e. via a StackTrace method that
+// returns a slice of program counters. The deepest stack trace in err's chain is used since it's the closest to where
+// the error was created, and nil is returned if there isn't any.
+func externalStack(err error, depth int) *stack {
+	var pcs []uintptr
+	for ; err != nil; err = unwrapCause(err) {
+		if errPCs := stackTracePCs(err); len(errPCs) > 0 {
+			pcs = errPCs
+		}
+	}
+	if pcs == nil {
+		return nil
+	}
+	if depth < 1 {
+		depth = int(atomic.LoadInt32(&maxStackDepth))
+	}
+	if len(pcs) > depth {
+		return &stack{
+			pcs:       pcs[:depth],
+			truncated: len(pcs) - depth,
+		}
+	}
+	return &stack{pcs: pcs}
+}
+
+// stackTracePCs returns the program counters returned by err's StackTrace method, if it has one. The method is
+// detected via reflection so any slice type with uintptr elements is supported (e.g. errors.StackTrace of
+// github.com/pkg/errors) without importing the package that defines it.
+func stackTracePCs(err error) []uintptr {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil
+	}
+	typ := method.Type()
+	if typ.NumIn() != 0 || typ.NumOut() != 1 || typ.Out(0).Kind() != reflect.Slice ||
+		typ.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	trace := method.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	return pcs
 }
 
-func (f frame) get() *StackFrame {
-	fn := runtime.FuncForPC(f.pc())
-	if fn == nil {
-		return &StackFrame{
+// frameCache maps program counters to their symbolized stack frames. Symbolizing a program counter always gives the
+// same result, so the cache is shared by all errors and each program counter is only symbolized once.
+var frameCache sync.Map // map[uintptr][]StackFrame
+
+// symbolize returns the stack frames for a program counter returned by runtime.Callers, innermost first. A single
+// program counter stands for several logical frames if it's located in a function that was inlined by the compiler,
+// so the frames are resolved via runtime.CallersFrames rather than runtime.FuncForPC.
+func symbolize(pc uintptr) []StackFrame {
+	if sFrames, ok := frameCache.Load(pc); ok {
+		return sFrames.([]StackFrame)
+	}
+
+	var sFrames []StackFrame
+	rFrames := runtime.CallersFrames([]uintptr{pc})
+	for {
+		rFrame, more := rFrames.Next()
+		if rFrame.Function != "" {
+			sFrames = append(sFrames, newStackFrame(rFrame.Function, rFrame.File, rFrame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+	if len(sFrames) == 0 {
+		sFrames = []StackFrame{{
 			Name: "unknown",
 			File: "unknown",
-		}
+		}}
 	}
+	frameCache.Store(pc, sFrames)
+	return sFrames
+}
 
-	name := fn.Name()
-	i := strings.LastIndex(name, "/")
-	name = name[i+1:]
-	file, line := fn.FileLine(f.pc())
+// frame is a single program counter of a stack frame. Frames of errors decoded from JSON don't have a program counter
+// and hold the decoded stack frame instead.
+type frame struct {
+	pc      uintptr
+	decoded *StackFrame
+}
 
-	return &StackFrame{
-		Name: name,
-		File: file,
-		Line: line,
+// get returns the innermost stack frame of the program counter, which is the frame of the function call that was
+// recorded.
+func (f *frame) get() *StackFrame {
+	if f.decoded != nil {
+		sFrame := *f.decoded
+		return &sFrame
 	}
+	sFrame := symbolize(f.pc)[0]
+	rewritePath(&sFrame)
+	return &sFrame
+}
+
+// stack is an array of program counters along with the number of frames that were left out because the
+// maximum stack depth was reached. Stacks of errors decoded from JSON hold the decoded stack frames instead.
+type stack struct {
+	pcs       []uintptr
+	decoded   []StackFrame
+	truncated int
 }
 
-// stack is an array of program counters.
-type stack []uintptr
+// frame returns the innermost frame of the stack trace.
+func (s *stack) frame() *frame {
+	if len(s.decoded) > 0 {
+		return &frame{decoded: &s.decoded[0]}
+	}
+	if len(s.pcs) > 0 {
+		return &frame{pc: s.pcs[0]}
+	}
+	return &frame{decoded: &StackFrame{Name: "unknown", File: "unknown"}}
+}
 
 func (s *stack) get() []StackFrame {
+	if s.decoded != nil {
+		return append([]StackFrame(nil), s.decoded...)
+	}
 	var sFrames []StackFrame
-	for _, f := range *s {
-		frame := frame(f)
-		sFrame := frame.get()
-		sFrames = append(sFrames, *sFrame)
+	for _, pc := range s.pcs {
+		sFrames = append(sFrames, symbolize(pc)...)
+	}
+	for i := range sFrames {
+		rewritePath(&sFrames[i])
 	}
 	return sFrames
 }
@@ -0,0 +1,32 @@
+//go:build go1.21
+// +build go1.21
+
+package eris_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func TestStackFrameNamesGenerics(t *testing.T) {
+	var err error
+	slices.SortFunc([]int{2, 1}, func(a, b int) int {
+		if err == nil {
+			err = eris.New("root error")
+		}
+		return a - b
+	})
+
+	for _, frame := range eris.Unpack(err).ErrRoot.Stack {
+		if frame.Package == "slices" && strings.HasSuffix(frame.Function, "[...]") {
+			if !strings.HasPrefix(frame.Name, "slices.") || !strings.HasSuffix(frame.Name, "[...]") {
+				t.Errorf("unexpected name { %v } for generic function { %v }", frame.Name, frame.Function)
+			}
+			return
+		}
+	}
+	t.Errorf("expected a generic function in the stack trace { %v }", eris.Unpack(err).ErrRoot.Stack)
+}
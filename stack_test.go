@@ -0,0 +1,408 @@
+package eris_test
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+	"github.com/rotisserie/eris/internal/testpkg.v2"
+)
+
+// The following helpers are small enough to be inlined by the compiler.
+
+func inlinedNew() error {
+	return eris.New("root error")
+}
+
+func inlinedWrap(err error) error {
+	return eris.Wrap(err, "additional context")
+}
+
+func inlinedWrapNew() error {
+	return inlinedWrap(inlinedNew())
+}
+
+func frameNames(frames []eris.StackFrame) []string {
+	var names []string
+	for _, f := range frames {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func TestStackInlining(t *testing.T) {
+	tests := map[string]struct {
+		err   error    // error created through inlined calls
+		frame string   // expected name of the wrap frame
+		stack []string // expected names of the first root stack frames
+	}{
+		"inlined root error": {
+			err:   inlinedNew(),
+			stack: []string{"eris_test.inlinedNew", "eris_test.TestStackInlining"},
+		},
+		"inlined wrap error": {
+			err:   inlinedWrap(eris.New("root error")),
+			frame: "eris_test.inlinedWrap",
+			stack: []string{"eris_test.inlinedWrap", "eris_test.TestStackInlining"},
+		},
+		"nested inlined calls": {
+			err:   inlinedWrapNew(),
+			frame: "eris_test.inlinedWrap",
+			stack: []string{"eris_test.inlinedWrap", "eris_test.inlinedWrapNew", "eris_test.TestStackInlining"},
+		},
+	}
+
+	for desc, tc := range tests {
+		uErr := eris.Unpack(tc.err)
+		if tc.frame != "" {
+			if uErr.ErrChain == nil || len(*uErr.ErrChain) != 1 {
+				t.Fatalf("%v: expected a single wrap error but got { %v }", desc, uErr.ErrChain)
+			}
+			if name := (*uErr.ErrChain)[0].Frame.Name; name != tc.frame {
+				t.Errorf("%v: expected wrap frame { %v } got { %v }", desc, tc.frame, name)
+			}
+		}
+		names := frameNames(uErr.ErrRoot.Stack)
+		if len(names) < len(tc.stack) {
+			t.Fatalf("%v: expected stack to start with { %v } got { %v }", desc, tc.stack, names)
+		}
+		for i, name := range tc.stack {
+			if names[i] != name {
+				t.Errorf("%v: expected stack to start with { %v } got { %v }", desc, tc.stack, names)
+				break
+			}
+		}
+	}
+}
+
+func recurse(n int, fn func() error) error {
+	if n == 0 {
+		return fn()
+	}
+	return recurse(n-1, fn)
+}
+
+func TestStackDepth(t *testing.T) {
+	full := eris.Unpack(recurse(100, func() error { return eris.NewWithDepth("root error", 1000) })).ErrRoot
+	if full.Truncated != 0 || len(full.Stack) < 100 {
+		t.Fatalf("expected full stack trace but got %v frames with %v truncated", len(full.Stack), full.Truncated)
+	}
+
+	tests := map[string]struct {
+		err   func() error // creates an error deep in the stack
+		depth int          // package-level maximum depth
+		stack int          // expected number of frames
+	}{
+		"default depth": {
+			err:   func() error { return eris.New("root error") },
+			stack: eris.DefaultStackDepth,
+		},
+		"package-level depth": {
+			err:   func() error { return eris.New("root error") },
+			depth: 10,
+			stack: 10,
+		},
+		"per-call depth": {
+			err:   func() error { return eris.NewWithDepth("root error", 5) },
+			stack: 5,
+		},
+		"per-call depth when wrapping": {
+			err:   func() error { return eris.WrapWithDepth(errors.New("external error"), "additional context", 5) },
+			stack: 5,
+		},
+		"stack shorter than depth": {
+			err:   func() error { return eris.NewWithDepth("root error", 1000) },
+			stack: len(full.Stack),
+		},
+	}
+
+	for desc, tc := range tests {
+		eris.SetMaxStackDepth(tc.depth)
+		root := eris.Unpack(recurse(100, tc.err)).ErrRoot
+		if len(root.Stack) != tc.stack {
+			t.Errorf("%v: expected %v frames got %v", desc, tc.stack, len(root.Stack))
+		}
+		if truncated := len(full.Stack) - tc.stack; root.Truncated != truncated {
+			t.Errorf("%v: expected %v truncated frames got %v", desc, truncated, root.Truncated)
+		}
+	}
+	eris.SetMaxStackDepth(0)
+}
+
+type frameTester struct{}
+
+func (frameTester) valueMethod() error {
+	return eris.New("root error")
+}
+
+func (*frameTester) pointerMethod() error {
+	return eris.New("root error")
+}
+
+func TestStackFrameNames(t *testing.T) {
+	closure := func() error {
+		return eris.New("root error")
+	}
+	nestedClosure := func() error {
+		return func() error {
+			return eris.New("root error")
+		}()
+	}
+
+	tests := map[string]struct {
+		err      error           // error created in the function under test
+		frame    eris.StackFrame // expected first frame of the root stack (without file and line)
+		fullName string          // expected fully qualified name
+	}{
+		"function": {
+			err: inlinedNew(),
+			frame: eris.StackFrame{
+				Name:     "eris_test.inlinedNew",
+				Package:  "github.com/rotisserie/eris_test",
+				Function: "inlinedNew",
+			},
+			fullName: "github.com/rotisserie/eris_test.inlinedNew",
+		},
+		"value receiver": {
+			err: frameTester{}.valueMethod(),
+			frame: eris.StackFrame{
+				Name:     "eris_test.frameTester.valueMethod",
+				Package:  "github.com/rotisserie/eris_test",
+				Receiver: "frameTester",
+				Function: "valueMethod",
+			},
+			fullName: "github.com/rotisserie/eris_test.frameTester.valueMethod",
+		},
+		"pointer receiver": {
+			err: (&frameTester{}).pointerMethod(),
+			frame: eris.StackFrame{
+				Name:     "eris_test.(*frameTester).pointerMethod",
+				Package:  "github.com/rotisserie/eris_test",
+				Receiver: "*frameTester",
+				Function: "pointerMethod",
+			},
+			fullName: "github.com/rotisserie/eris_test.(*frameTester).pointerMethod",
+		},
+		"closure": {
+			err: closure(),
+			frame: eris.StackFrame{
+				Name:     "eris_test.TestStackFrameNames (closure 1)",
+				Package:  "github.com/rotisserie/eris_test",
+				Function: "TestStackFrameNames.func1",
+			},
+			fullName: "github.com/rotisserie/eris_test.TestStackFrameNames (closure 1)",
+		},
+		"nested closure": {
+			err: nestedClosure(),
+			frame: eris.StackFrame{
+				Name:     "eris_test.TestStackFrameNames (closure 2.1)",
+				Package:  "github.com/rotisserie/eris_test",
+				Function: "TestStackFrameNames.func2.func1",
+			},
+			fullName: "github.com/rotisserie/eris_test.TestStackFrameNames (closure 2.1)",
+		},
+		"dotted package path": {
+			err: testpkg.New(),
+			frame: eris.StackFrame{
+				Name:     "testpkg.v2.New",
+				Package:  "github.com/rotisserie/eris/internal/testpkg.v2",
+				Function: "New",
+			},
+			fullName: "github.com/rotisserie/eris/internal/testpkg.v2.New",
+		},
+	}
+
+	for desc, tc := range tests {
+		frame := eris.Unpack(tc.err).ErrRoot.Stack[0]
+		if fullName := frame.FullName(); fullName != tc.fullName {
+			t.Errorf("%v: expected full name { %v } got { %v }", desc, tc.fullName, fullName)
+		}
+		// locations are covered by the other tests and the build-time path isn't part of the frame's identity
+		frame = eris.StackFrame{Name: frame.Name, Package: frame.Package, Receiver: frame.Receiver, Function: frame.Function}
+		if frame != tc.frame {
+			t.Errorf("%v: expected frame { %+v } got { %+v }", desc, tc.frame, frame)
+		}
+	}
+}
+
+func TestDottedPackagePath(t *testing.T) {
+	uErr := eris.Unpack(testpkg.New())
+
+	// the module-relative path is only found with the unescaped package path
+	if frame := uErr.ErrRoot.Stack[0]; frame.File != "internal/testpkg.v2/testpkg.go" {
+		t.Errorf("expected a module-relative path but got { %v }", frame.File)
+	}
+
+	format := eris.NewDefaultFormat(true)
+	format.Filters = []eris.FrameFilter{eris.HidePackages("github.com/rotisserie/eris/internal/testpkg.v2")}
+	if str := uErr.ToString(format); strings.Contains(str, "testpkg") {
+		t.Errorf("expected the frames of the dotted package to be hidden { %v }", str)
+	}
+}
+
+// pkgFrame and pkgStackTrace mirror the stack trace types of github.com/pkg/errors.
+type pkgFrame uintptr
+
+type pkgStackTrace []pkgFrame
+
+// pkgError mirrors the errors of github.com/pkg/errors, which record a stack trace and expose their cause via a Cause
+// method rather than Unwrap.
+type pkgError struct {
+	msg   string
+	cause error
+	pcs   []uintptr
+}
+
+func pkgWrap(cause error, msg string) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &pkgError{msg: msg, cause: cause, pcs: pcs[:n]}
+}
+
+func (e *pkgError) Error() string {
+	if e.cause == nil {
+		return e.msg
+	}
+	return e.msg + ": " + e.cause.Error()
+}
+
+func (e *pkgError) Cause() error {
+	return e.cause
+}
+
+func (e *pkgError) StackTrace() pkgStackTrace {
+	trace := make(pkgStackTrace, len(e.pcs))
+	for i, pc := range e.pcs {
+		trace[i] = pkgFrame(pc)
+	}
+	return trace
+}
+
+func pkgCreate() error {
+	return pkgWrap(nil, "pkg error")
+}
+
+func TestExternalStackTrace(t *testing.T) {
+	pkgRoot := pkgCreate()
+	pkgErr := pkgWrap(pkgRoot, "pkg context")
+
+	tests := map[string]struct {
+		input     error // error containing the external error
+		truncated bool  // whether the stack trace is expected to be truncated
+	}{
+		"wrapped error": {
+			input: eris.Wrap(pkgErr, "additional context"),
+		},
+		"wrapped error with depth": {
+			input:     eris.WrapWithDepth(pkgErr, "additional context", 1),
+			truncated: true,
+		},
+		"multi-error": {
+			input: eris.Join(pkgErr, eris.New("root error")),
+		},
+		"error with fields": {
+			input: eris.WithFields(pkgErr, map[string]interface{}{"user": "alice"}),
+		},
+	}
+
+	for desc, tc := range tests {
+		uErr := eris.Unpack(tc.input)
+		if uErr.ErrBranches != nil {
+			uErr = uErr.ErrBranches[0]
+		}
+		if uErr.ErrRoot == nil || uErr.ErrRoot.Msg != "pkg context: pkg error" {
+			t.Fatalf("%v: expected the external error as root error { %+v }", desc, uErr.ErrRoot)
+		}
+		if name := uErr.ErrRoot.Stack[0].Name; name != "eris_test.pkgCreate" {
+			t.Errorf("%v: expected the stack trace of the external error to be reused, got frame { %v }", desc, name)
+		}
+		if truncated := uErr.ErrRoot.Truncated > 0; truncated != tc.truncated {
+			t.Errorf("%v: expected truncated stack trace %v but got %v", desc, tc.truncated, truncated)
+		}
+		if cause := eris.Cause(tc.input); cause != pkgRoot {
+			t.Errorf("%v: expected cause { %v } got { %v }", desc, pkgRoot, cause)
+		}
+	}
+}
+
+func TestUnpackCauseChain(t *testing.T) {
+	err := eris.Wrap(pkgWrap(eris.New("root error"), "pkg context"), "additional context")
+	uErr := eris.Unpack(err)
+	if uErr.ErrChain == nil || len(*uErr.ErrChain) != 2 {
+		t.Fatalf("expected a chain of two links { %+v }", uErr.ErrChain)
+	}
+	if link := (*uErr.ErrChain)[1]; link.Msg != "pkg context" || link.Type != "*eris_test.pkgError" {
+		t.Errorf("expected the external error in the chain but got { %+v }", link)
+	}
+	if uErr.ErrRoot == nil || uErr.ErrRoot.Msg != "root error" {
+		t.Errorf("expected the root error beneath the external error { %+v }", uErr.ErrRoot)
+	}
+	if cause := eris.Cause(err); cause == nil || cause.Error() != "root error" {
+		t.Errorf("expected the root error as cause but got { %v }", cause)
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	pkgErr := pkgCreate()
+
+	tests := map[string]struct {
+		input error  // input error
+		frame string // expected first frame (no stack trace if empty)
+	}{
+		"root error": {
+			input: inlinedNew(),
+			frame: "eris_test.inlinedNew",
+		},
+		"wrapped error": {
+			input: inlinedWrap(inlinedNew()),
+			frame: "eris_test.inlinedWrap",
+		},
+		"multi-error": {
+			input: eris.Join(errors.New("external error"), inlinedNew()),
+			frame: "eris_test.TestStackTrace",
+		},
+		"external error with stack trace": {
+			input: pkgErr,
+			frame: "eris_test.pkgCreate",
+		},
+		"external error": {
+			input: errors.New("external error"),
+		},
+		"nil error": {},
+	}
+
+	for desc, tc := range tests {
+		frames := eris.StackTrace(tc.input)
+		pcs := eris.StackPCs(tc.input)
+		if tc.frame == "" {
+			if frames != nil || pcs != nil {
+				t.Errorf("%v: expected no stack trace but got { %v } { %v }", desc, frames, pcs)
+			}
+			continue
+		}
+		if len(frames) == 0 || frames[0].Name != tc.frame {
+			t.Errorf("%v: expected stack trace starting with { %v } got { %v }", desc, tc.frame, frames)
+		}
+		rFrame, _ := runtime.CallersFrames(pcs).Next()
+		if !strings.HasSuffix(rFrame.Function, strings.TrimPrefix(tc.frame, "eris_test")) {
+			t.Errorf("%v: expected program counters starting in { %v } got { %v }", desc, tc.frame, rFrame.Function)
+		}
+		if root := eris.Unpack(tc.input).ErrRoot; root != nil && !reflect.DeepEqual(root.Stack, frames) {
+			t.Errorf("%v: expected the stack trace of eris.Unpack { %v } got { %v }", desc, root.Stack, frames)
+		}
+
+		// error reporters detect the StackTrace method via reflection
+		method := reflect.ValueOf(tc.input).MethodByName("StackTrace")
+		if !method.IsValid() {
+			t.Errorf("%v: expected a StackTrace method", desc)
+			continue
+		}
+		trace := method.Call(nil)[0]
+		if trace.Kind() != reflect.Slice || trace.Len() != len(pcs) || uintptr(trace.Index(0).Uint()) != pcs[0] {
+			t.Errorf("%v: expected StackTrace to return the program counters { %v } got { %v }", desc, pcs, trace)
+		}
+	}
+}
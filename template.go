@@ -0,0 +1,97 @@
+package eris
+
+import (
+	"io"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// DefaultTemplate is a template for NewTemplateFormat that produces the same output as NewDefaultFormat(false).
+const DefaultTemplate = `{{range .Chain}}{{if or .Msg (not .Type)}}{{.Msg}}: {{end}}{{end}}` +
+	`{{with .ErrRoot}}{{.Msg}}{{end}}` +
+	`{{if not .ErrRoot}}{{range $i, $branch := .ErrBranches}}{{if $i}}; {{end}}{{format $branch}}{{end}}{{end}}` +
+	`{{.ExternalErr}}`
+
+// DefaultTraceTemplate is a template for NewTemplateFormat that produces the same output as NewDefaultFormat(true).
+const DefaultTraceTemplate = "{{range .Chain}}{{if not .Type}}{{.Msg}}\n\t{{frame .Frame}}\n" +
+	"{{else if .Msg}}{{.Msg}}\n{{end}}{{end}}" +
+	"{{with .ErrRoot}}{{.Msg}}\n{{range $.Stack}}\t{{frame .}}\n{{end}}" +
+	"{{if .Truncated}}\t{{truncated .Truncated}}\n{{end}}{{end}}" +
+	"{{range .ErrBranches}}{{format . | indent \"\\t\"}}{{end}}" +
+	"{{.ExternalErr}}"
+
+// TemplateData is the data that templates of a TemplateFormat are executed with. It embeds the unpacked error, so its
+// fields such as ErrRoot and Fields can be used directly. Chain holds the links of ErrChain, and Stack holds the frames
+// of the root error's stack trace that aren't hidden by the filters set via SetFrameFilters.
+type TemplateData struct {
+	UnpackedError
+	Chain []ErrLink
+	Stack []StackFrame
+}
+
+// TemplateFormat is a Formatter that writes errors using a text/template template.
+type TemplateFormat struct {
+	tmpl *template.Template
+}
+
+// NewTemplateFormat parses text as a text/template template executed with TemplateData for each formatted error. The
+// following functions are available in addition to the predefined functions of text/template:
+//
+//	frame StackFrame         formats a stack frame as "name: file: line"
+//	shortpath string         returns the file name of a path without its directory
+//	indent prefix string     prefixes each line of a string with prefix
+//	truncated int            returns the marker printed in place of frames left out of a stack trace
+//	format UnpackedError     formats another error (e.g. one of ErrBranches) with the same template
+//
+// For example, the following template prints the message and location of each link in the chain:
+//
+//	{{range .Chain}}{{.Msg}} at {{shortpath .Frame.File}}:{{.Frame.Line}}
+//	{{end}}{{.ErrRoot.Msg}}
+//
+// DefaultTemplate and DefaultTraceTemplate reproduce the default formats and can serve as a starting point.
+func NewTemplateFormat(text string) (*TemplateFormat, error) {
+	tf := &TemplateFormat{}
+	tmpl, err := template.New("eris").Funcs(template.FuncMap{
+		"frame":     templateFrame,
+		"shortpath": path.Base,
+		"indent":    templateIndent,
+		"truncated": formatTruncated,
+		"format":    tf.format,
+	}).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	tf.tmpl = tmpl
+	return tf, nil
+}
+
+// FormatError implements Formatter by executing the template with the unpacked error.
+func (tf *TemplateFormat) FormatError(w io.Writer, err UnpackedError) error {
+	data := TemplateData{
+		UnpackedError: err,
+	}
+	if err.ErrChain != nil {
+		data.Chain = *err.ErrChain
+	}
+	if err.ErrRoot != nil {
+		data.Stack = filterFrames(err.ErrRoot.Stack, FrameOptions{})
+	}
+	return tf.tmpl.Execute(w, data)
+}
+
+func (tf *TemplateFormat) format(err UnpackedError) (string, error) {
+	var b strings.Builder
+	if execErr := tf.FormatError(&b, err); execErr != nil {
+		return "", execErr
+	}
+	return b.String(), nil
+}
+
+func templateFrame(sFrame StackFrame) string {
+	return sFrame.formatFrame(Format{TSep: ": "})
+}
+
+func templateIndent(prefix string, str string) string {
+	return indent(str, prefix)
+}
@@ -0,0 +1,134 @@
+package eris_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rotisserie/eris"
+)
+
+func TestDefaultTemplates(t *testing.T) {
+	globalErr := eris.New("global error")
+
+	tests := map[string]struct {
+		input error // input error
+		depth int   // max stack depth (default if zero)
+	}{
+		"root error": {
+			input: eris.New("root error"),
+		},
+		"wrapped error": {
+			input: setupTestCase(false, eris.New("root error"), []string{"additional context", "even more context"}),
+		},
+		"wrapped global error": {
+			input: eris.Wrap(globalErr, "additional context"),
+		},
+		"empty message": {
+			input: eris.Wrap(globalErr, ""),
+		},
+		"external error": {
+			input: eris.Wrap(io.EOF, "additional context"),
+		},
+		"external error in the chain": {
+			input: eris.Wrap(fmt.Errorf("external context: %w", eris.Wrap(globalErr, "context")), "more context"),
+		},
+		"external error without message": {
+			input: fmt.Errorf("%w", globalErr),
+		},
+		"unwrapped external error": {
+			input: errors.New("external error"),
+		},
+		"joined errors": {
+			input: eris.Wrap(eris.Join(eris.Wrap(globalErr, "additional context"), io.EOF), "even more context"),
+		},
+		"nested joined errors": {
+			input: eris.Join(eris.Join(globalErr, io.EOF), eris.New("root error")),
+		},
+		"truncated stack": {
+			input: eris.New("root error"),
+			depth: 1,
+		},
+	}
+
+	short, err := eris.NewTemplateFormat(eris.DefaultTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	full, err := eris.NewTemplateFormat(eris.DefaultTraceTemplate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for desc, tc := range tests {
+		input := tc.input
+		if tc.depth > 0 {
+			eris.SetMaxStackDepth(tc.depth)
+			input = eris.New("root error")
+			eris.SetMaxStackDepth(0)
+		}
+		uErr := eris.Unpack(input)
+		for _, tf := range []struct {
+			format    *eris.TemplateFormat
+			withTrace bool
+		}{{short, false}, {full, true}} {
+			var b strings.Builder
+			if err := tf.format.FormatError(&b, uErr); err != nil {
+				t.Fatalf("%v: unexpected error: %v", desc, err)
+			}
+			if expected := uErr.ToString(eris.NewDefaultFormat(tf.withTrace)); b.String() != expected {
+				t.Errorf("%v (trace %v): expected { %q } got { %q }", desc, tf.withTrace, expected, b.String())
+			}
+		}
+	}
+}
+
+func TestTemplateFormat(t *testing.T) {
+	err := eris.WithFields(eris.Wrap(eris.New("root error"), "additional context"), map[string]interface{}{"id": 1})
+
+	tests := map[string]struct {
+		text   string // template text
+		output string // expected output
+	}{
+		"messages": {
+			text:   "{{range .Chain}}{{.Msg}} at {{shortpath .Frame.File}}\n{{end}}{{.ErrRoot.Msg}}",
+			output: "additional context at template_test.go\nroot error",
+		},
+		"fields": {
+			text:   "{{.ErrRoot.Msg}} (id {{.Fields.id}})",
+			output: "root error (id 1)",
+		},
+		"frames": {
+			text:   "{{with index .Stack 0}}{{frame .}}{{end}}",
+			output: "eris_test.TestTemplateFormat: template_test.go: 89",
+		},
+		"indent": {
+			text:   `{{indent "> " "first\nsecond"}}`,
+			output: "> first\n> second",
+		},
+		"truncated": {
+			text:   "{{truncated 2}}",
+			output: "... 2 more frames",
+		},
+	}
+
+	for desc, tc := range tests {
+		tf, parseErr := eris.NewTemplateFormat(tc.text)
+		if parseErr != nil {
+			t.Fatalf("%v: unexpected error: %v", desc, parseErr)
+		}
+		var b strings.Builder
+		if execErr := tf.FormatError(&b, eris.Unpack(err)); execErr != nil {
+			t.Fatalf("%v: unexpected error: %v", desc, execErr)
+		}
+		if b.String() != tc.output {
+			t.Errorf("%v: expected { %q } got { %q }", desc, tc.output, b.String())
+		}
+	}
+
+	if _, parseErr := eris.NewTemplateFormat("{{.Chain"); parseErr == nil {
+		t.Errorf("expected an error for an invalid template")
+	}
+}